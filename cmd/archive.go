@@ -23,8 +23,6 @@ var archiveCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"mv"},
 	Run: func(cmd *cobra.Command, args []string) {
-		archiveId := args[0]
-
 		config, err := internal.LoadConfig()
 		if err != nil {
 			log.Printf("❌ Error loading config: %v", err)
@@ -46,6 +44,12 @@ var archiveCmd = &cobra.Command{
 			return
 		}
 
+		archiveId, err := resolveNoteID(zettels, args[0])
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
 		// Search for the note
 		found := false
 		for i := range zettels {
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var boardProject string
+var boardInteractive bool
+
+var (
+	boardColumnStyle = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(lipgloss.Color("240")).
+				Padding(0, 1)
+	boardFocusedColumnStyle = boardColumnStyle.
+				BorderForeground(lipgloss.Color("39"))
+	boardHeaderStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	boardCardStyle     = lipgloss.NewStyle()
+	boardSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	boardMetaStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	boardOverdueStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// Load the tasks shown on the board, grouped by status
+func loadBoardColumns(zettels []internal.Zettel, config internal.Config) ([]internal.TaskColumn, error) {
+	query := internal.TaskQuery{Project: boardProject, Sort: "priority"}
+	tasks, err := internal.QueryTasks(zettels, query, config, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return internal.GroupTasksByStatus(tasks, config.TaskStatuses()), nil
+}
+
+// Get the terminal width (falls back to 120 columns)
+func terminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	return 120
+}
+
+// Render task columns side by side; focusCol/focusRow mark the selected card (-1 for none)
+func renderBoard(columns []internal.TaskColumn, width, focusCol, focusRow int, config internal.Config) string {
+	if len(columns) == 0 {
+		return ""
+	}
+
+	// Border (2) and padding (2) are drawn around each column
+	columnWidth := width/len(columns) - 4
+	if columnWidth < 16 {
+		columnWidth = 16
+	}
+
+	now := time.Now()
+	rendered := make([]string, len(columns))
+	for c, column := range columns {
+		lines := []string{
+			boardHeaderStyle.Render(fmt.Sprintf("%s (%d)", column.Status, len(column.Tasks))),
+			"",
+		}
+
+		for r, task := range column.Tasks {
+			card := boardCardStyle.Width(columnWidth).Render(fmt.Sprintf("#%s %s", task.ID, task.Title))
+			if c == focusCol && r == focusRow {
+				card = boardSelectedStyle.Width(columnWidth).Render(fmt.Sprintf("#%s %s", task.ID, task.Title))
+			}
+			lines = append(lines, card)
+
+			var meta []string
+			if task.Priority != "" {
+				meta = append(meta, task.Priority)
+			}
+			if task.Due != "" {
+				due := "due " + task.Due
+				if internal.IsOverdue(task, config.TaskDoneStatus(), now) {
+					due = boardOverdueStyle.Render(due)
+				}
+				meta = append(meta, due)
+			}
+			if len(meta) > 0 {
+				lines = append(lines, boardMetaStyle.Render(strings.Join(meta, " · ")))
+			}
+			lines = append(lines, "")
+		}
+
+		style := boardColumnStyle
+		if c == focusCol {
+			style = boardFocusedColumnStyle
+		}
+		rendered[c] = style.Width(columnWidth + 2).Render(strings.Join(lines, "\n"))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+// Interactive board state
+type boardModel struct {
+	config  internal.Config
+	zettels []internal.Zettel
+	columns []internal.TaskColumn
+	col     int
+	row     int
+	width   int
+	message string
+	err     error
+}
+
+func (m boardModel) Init() tea.Cmd {
+	return nil
+}
+
+// Keep the cursor inside the current column
+func (m *boardModel) clampCursor() {
+	if m.col >= len(m.columns) {
+		m.col = len(m.columns) - 1
+	}
+	if m.col < 0 {
+		m.col = 0
+	}
+	if m.row >= len(m.columns[m.col].Tasks) {
+		m.row = len(m.columns[m.col].Tasks) - 1
+	}
+	if m.row < 0 {
+		m.row = 0
+	}
+}
+
+// Move the selected task to the neighbouring column and persist the new status.
+// Tasks only move between the workflow columns; the columns after them hold
+// tasks with statuses outside the workflow.
+func (m *boardModel) moveTask(delta int) {
+	workflow := len(m.config.TaskStatuses())
+	target := m.col + delta
+	if target < 0 || target >= workflow || len(m.columns[m.col].Tasks) == 0 {
+		return
+	}
+
+	task := m.columns[m.col].Tasks[m.row]
+	if m.col >= workflow {
+		m.message = fmt.Sprintf("⚠️ #%s has a status outside the workflow; use `zk task status` to fix it", task.ID)
+		return
+	}
+	status, err := internal.NormalizeTaskStatus(m.columns[target].Status, m.config.TaskStatuses())
+	if err != nil {
+		m.message = fmt.Sprintf("❌ %v", err)
+		return
+	}
+
+	for i := range m.zettels {
+		if m.zettels[i].ID != task.ID {
+			continue
+		}
+
+		next, err := changeTaskStatus(m.zettels, i, status, m.config)
+		if err != nil {
+			m.message = fmt.Sprintf("❌ %v", err)
+			return
+		}
+		m.message = fmt.Sprintf("✅ #%s moved to %s", task.ID, status)
+		if next != nil {
+			m.message += fmt.Sprintf(" (🔁 next due %s)", next.Due)
+		}
+		break
+	}
+
+	// Reload so that spawned recurring tasks show up
+	zettels, err := internal.LoadJson(m.config)
+	if err != nil {
+		m.err = err
+		return
+	}
+	columns, err := loadBoardColumns(zettels, m.config)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.zettels = zettels
+	m.columns = columns
+
+	// Follow the moved task
+	m.col = target
+	m.row = 0
+	for r, t := range m.columns[target].Tasks {
+		if t.ID == task.ID {
+			m.row = r
+			break
+		}
+	}
+}
+
+func (m boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.row--
+		case "down", "j":
+			m.row++
+		case "tab":
+			m.col = (m.col + 1) % len(m.columns)
+			m.row = 0
+		case "shift+tab":
+			m.col = (m.col - 1 + len(m.columns)) % len(m.columns)
+			m.row = 0
+		case "left", "h":
+			m.moveTask(-1)
+		case "right", "l":
+			m.moveTask(1)
+		}
+		if m.err != nil {
+			return m, tea.Quit
+		}
+		m.clampCursor()
+	}
+	return m, nil
+}
+
+func (m boardModel) View() string {
+	var b strings.Builder
+	b.WriteString(renderBoard(m.columns, m.width, m.col, m.row, m.config))
+	b.WriteString("\n")
+	b.WriteString(boardMetaStyle.Render("↑/↓ select · tab/shift+tab switch column · ←/→ move task · q quit"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.message)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+var taskBoardCmd = &cobra.Command{
+	Use:     "board",
+	Short:   "Show tasks as a kanban board",
+	Aliases: []string{"b"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if machineOutput() {
+			log.Printf("❌ The board has no --output %s; use `zk task list --output %s`", outputFormat, outputFormat)
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			return
+		}
+
+		columns, err := loadBoardColumns(zettels, *config)
+		if err != nil {
+			log.Printf("❌ Error: %v", err)
+			return
+		}
+
+		if !boardInteractive || !term.IsTerminal(int(os.Stdout.Fd())) {
+			fmt.Println(renderBoard(columns, terminalWidth(), -1, -1, *config))
+			return
+		}
+
+		// Log lines would break the TUI layout; status messages are shown in the view instead
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stderr)
+
+		model := boardModel{config: *config, zettels: zettels, columns: columns, width: terminalWidth()}
+		final, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		log.SetOutput(os.Stderr)
+		if err != nil {
+			log.Printf("❌ Failed to run board: %v", err)
+			return
+		}
+		if m, ok := final.(boardModel); ok && m.err != nil {
+			log.Printf("❌ Error: %v", m.err)
+		}
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskBoardCmd)
+	taskBoardCmd.Flags().StringVar(&boardProject, "project", "", "Show only tasks of a project")
+	taskBoardCmd.Flags().BoolVarP(&boardInteractive, "interactive", "i", false, "Move tasks between columns with the arrow keys")
+}
@@ -23,8 +23,6 @@ var deleteCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"rm"},
 	Run: func(cmd *cobra.Command, args []string) {
-		deleteId := args[0]
-
 		config, err := internal.LoadConfig()
 		if err != nil {
 			log.Printf("❌ Error loading config: %v", err)
@@ -46,6 +44,12 @@ var deleteCmd = &cobra.Command{
 			return
 		}
 
+		deleteId, err := resolveNoteID(zettels, args[0])
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
 		// Search for the note
 		found := false
 		for i := range zettels {
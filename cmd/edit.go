@@ -43,13 +43,14 @@ func backupNote(notePath string, backupDir string) error {
 
 // editCmd represents the edit command
 var editCmd = &cobra.Command{
-	Use:     "edit [id]",
-	Short:   "Edit a note",
-	Args:    cobra.ExactArgs(1),
+	Use:   "edit [id]",
+	Short: "Edit a note",
+	Long: `Open a note in the configured editor.
+
+Without an ID, pick the note from an interactive fuzzy finder.`,
+	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"e"},
 	Run: func(cmd *cobra.Command, args []string) {
-		editId := args[0]
-
 		config, err := internal.LoadConfig()
 		if err != nil {
 			log.Printf("❌ Error loading config: %v", err)
@@ -64,6 +65,32 @@ var editCmd = &cobra.Command{
 			log.Printf("⚠️ Trash cleanup failed: %v", err)
 		}
 
+		// Load JSON data
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading notes from JSON: %v", err)
+			os.Exit(1)
+		}
+
+		var editId string
+		if len(args) == 0 {
+			picked, err := pickActiveNote(zettels, "Edit")
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			if picked == nil {
+				return
+			}
+			editId = picked.ID
+		} else {
+			editId, err = resolveNoteID(zettels, args[0])
+			if err != nil {
+				log.Printf("%v", err)
+				os.Exit(1)
+			}
+		}
+
 		dir := config.NoteDir
 		lockFile := filepath.Join(dir, editId+".lock")
 
@@ -72,13 +99,6 @@ var editCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Load JSON data
-		zettels, err := internal.LoadJson(*config)
-		if err != nil {
-			log.Printf("❌ Error loading notes from JSON: %v", err)
-			os.Exit(1)
-		}
-
 		found := false
 		for i := range zettels {
 			if editId == zettels[i].ID {
@@ -125,12 +145,7 @@ var editCmd = &cobra.Command{
 				}
 
 				// Update note metadata
-				zettels[i].Title = frontMatter.Title
-				zettels[i].NoteType = frontMatter.Type
-				zettels[i].Tags = frontMatter.Tags
-				zettels[i].Links = frontMatter.Links
-				zettels[i].TaskStatus = frontMatter.TaskStatus
-				zettels[i].UpdatedAt = frontMatter.UpdatedAt
+				zettels[i].CopyFrontMatter(frontMatter)
 
 				// Convert to JSON
 				updatedJson, err := json.MarshalIndent(zettels, "", "  ")
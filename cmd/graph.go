@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var graphFormat string
+var graphTags []string
+var graphAround string
+var graphDepth int
+var graphOut string
+var graphRels []string
+var graphTop int
+var graphHubsBy string
+var graphUndirected bool
+
+// Link graph of the active (not archived or deleted) notes
+func activeGraph(zettels []internal.Zettel) internal.Graph {
+	active := []internal.Zettel{}
+	for _, zettel := range zettels {
+		if !zettel.Deleted && !zettel.Archived {
+			active = append(active, zettel)
+		}
+	}
+	return internal.BuildGraph(active)
+}
+
+var graphCmd = &cobra.Command{
+	Use:     "graph",
+	Short:   "Explore the link graph of the notes",
+	Aliases: []string{"g"},
+}
+
+var graphExportCmd = &cobra.Command{
+	Use:   "export [query]",
+	Short: "Export the link graph",
+	Long: `Export the link graph as Graphviz DOT, GraphML, JSON or a Mermaid flowchart.
+
+Nodes carry the note type, tags and projects; edges carry where the link was
+found (front-matter, body or auto-link) and the relation of typed links.
+Notes can be filtered by a query, by tag, or to the neighbourhood of a note,
+and links by relation:
+
+  zk graph export --format dot | dot -Tsvg > graph.svg
+  zk graph export --format graphml --out zk.graphml 'type:permanent'
+  zk graph export --format mermaid --around 12 --depth 2
+  zk graph export --rel supports,contradicts`,
+	Aliases: []string{"ex"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.ValidateGraphFormat(graphFormat); err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		matches, err := internal.RunQuery(zettels, strings.Join(args, " "), *config)
+		if err != nil {
+			log.Printf("❌ Invalid query: %v", err)
+			os.Exit(1)
+		}
+		matched := make(map[string]bool)
+		for _, zettel := range matches {
+			if len(graphTags) > 0 {
+				hasTag := false
+				for _, tag := range graphTags {
+					if internal.HasTag(zettel.Tags, tag) {
+						hasTag = true
+						break
+					}
+				}
+				if !hasTag {
+					continue
+				}
+			}
+			matched[zettel.NoteID] = true
+		}
+
+		graph := activeGraph(zettels)
+		if len(graphRels) > 0 {
+			graph = graph.WithRelations(graphRels)
+		}
+		if graphAround != "" {
+			i, err := internal.ResolveNote(zettels, graphAround)
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			if !graph.HasNode(zettels[i].NoteID) {
+				log.Printf("❌ Note [%s] %s is archived or deleted", zettels[i].ID, zettels[i].Title)
+				os.Exit(1)
+			}
+			graph = graph.Neighbourhood(zettels[i].NoteID, graphDepth)
+		}
+		graph = graph.Subgraph(func(node internal.GraphNode) bool { return matched[node.NoteID] })
+
+		out := os.Stdout
+		if graphOut != "" {
+			file, err := os.Create(graphOut)
+			if err != nil {
+				log.Printf("❌ Failed to create %s: %v", graphOut, err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		if err := internal.WriteGraph(out, graphFormat, graph); err != nil {
+			log.Printf("❌ Error writing graph: %v", err)
+			os.Exit(1)
+		}
+		if graphOut != "" {
+			log.Printf("✅ Exported %d notes and %d links to %s", len(graph.Nodes), len(graph.Edges), graphOut)
+		}
+	},
+}
+
+// Format a note of the graph as `[id] Title`
+func graphNodeLabel(graph internal.Graph, noteID string) string {
+	node, _ := graph.Node(noteID)
+	return fmt.Sprintf("[%s] %s", node.ID, node.Title)
+}
+
+var graphStatsCmd = &cobra.Command{
+	Use:   "stats [query]",
+	Short: "Show statistics of the link graph",
+	Long: `Show the degree distribution, the top hubs by in-degree, out-degree and
+PageRank, the connected components and the communities detected by label
+propagation, optionally restricted to the notes matching a query.`,
+	Aliases: []string{"st"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if graphHubsBy != "pagerank" && graphHubsBy != "in" && graphHubsBy != "out" {
+			log.Printf("❌ Invalid --by %q: must be one of 'pagerank', 'in', 'out'", graphHubsBy)
+			os.Exit(1)
+		}
+		if graphTop < 1 {
+			log.Println("❌ --top must be at least 1")
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		matches, err := internal.RunQuery(zettels, strings.Join(args, " "), *config)
+		if err != nil {
+			log.Printf("❌ Invalid query: %v", err)
+			os.Exit(1)
+		}
+		graph := internal.BuildGraph(matches)
+		if len(graph.Nodes) == 0 && !machineOutput() {
+			log.Println("⚠️ No matching notes found.")
+			return
+		}
+
+		degrees := graph.Degrees()
+		ranks := graph.PageRank()
+		components := graph.Components()
+		communities := graph.Communities()
+
+		// Hubs first
+		sort.SliceStable(degrees, func(i, j int) bool {
+			a, b := degrees[i], degrees[j]
+			switch graphHubsBy {
+			case "in":
+				if a.In != b.In {
+					return a.In > b.In
+				}
+			case "out":
+				if a.Out != b.Out {
+					return a.Out > b.Out
+				}
+			}
+			return ranks[a.Node.NoteID] > ranks[b.Node.NoteID]
+		})
+
+		// Every note with its statistics, hubs first
+		if machineOutput() {
+			componentOf := make(map[string]int)
+			for i, component := range components {
+				for _, noteID := range component {
+					componentOf[noteID] = i + 1
+				}
+			}
+			communityOf := make(map[string]int)
+			for i, community := range communities {
+				for _, noteID := range community {
+					communityOf[noteID] = i + 1
+				}
+			}
+			records := []internal.GraphStatRecord{}
+			for _, degree := range degrees {
+				records = append(records, internal.GraphStatRecord{
+					ID:        degree.Node.ID,
+					NoteID:    degree.Node.NoteID,
+					Title:     degree.Node.Title,
+					In:        degree.In,
+					Out:       degree.Out,
+					PageRank:  ranks[degree.Node.NoteID],
+					Component: componentOf[degree.Node.NoteID],
+					Community: communityOf[degree.Node.NoteID],
+				})
+			}
+			printRecords(records)
+			return
+		}
+
+		titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
+		sectionStyle := color.New(color.FgHiGreen, color.Bold).SprintFunc()
+
+		orphans := 0
+		for _, degree := range degrees {
+			if degree.Total() == 0 {
+				orphans++
+			}
+		}
+		fmt.Printf("🕸️  %v\n", titleStyle("Link graph"))
+		fmt.Println(strings.Repeat("-", 50))
+		fmt.Printf("Notes: %d  Links: %d  Orphans: %d  Components: %d\n", len(graph.Nodes), len(graph.Edges), orphans, len(components))
+
+		// Degree distribution
+		fmt.Printf("\n%v\n", sectionStyle("Degree distribution"))
+		for _, bucket := range internal.DegreeDistribution(degrees) {
+			fmt.Printf("  %3d links  %-20s %d\n", bucket[0], strings.Repeat("█", min(bucket[1], 20)), bucket[1])
+		}
+
+		// Hubs
+		fmt.Printf("\n%v\n", sectionStyle(fmt.Sprintf("Top hubs by %s", graphHubsBy)))
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleDouble)
+		t.Style().Options.SeparateRows = false
+		t.AppendHeader(table.Row{"ID", "Title", "In", "Out", "PageRank"})
+		for _, degree := range degrees[:min(graphTop, len(degrees))] {
+			t.AppendRow(table.Row{degree.Node.ID, degree.Node.Title, degree.In, degree.Out, fmt.Sprintf("%.4f", ranks[degree.Node.NoteID])})
+		}
+		t.Render()
+
+		// Components with more than one note
+		fmt.Printf("\n%v\n", sectionStyle("Connected components"))
+		for i, component := range components {
+			if len(component) < 2 {
+				break
+			}
+			fmt.Printf("  %d. %d notes, e.g. %s\n", i+1, len(component), graphNodeLabel(graph, component[0]))
+		}
+		if orphans > 0 {
+			fmt.Printf("  + %d unlinked notes\n", orphans)
+		}
+
+		// Communities with more than one note
+		fmt.Printf("\n%v\n", sectionStyle("Communities"))
+		shown := 0
+		for _, community := range communities {
+			if len(community) < 2 || shown >= graphTop {
+				break
+			}
+			shown++
+			labels := []string{}
+			for _, noteID := range community {
+				labels = append(labels, graphNodeLabel(graph, noteID))
+			}
+			fmt.Printf("  %d. (%d notes) %s\n", shown, len(community), strings.Join(labels, ", "))
+		}
+		if shown == 0 {
+			fmt.Println("  No communities found.")
+		}
+	},
+}
+
+var graphPathCmd = &cobra.Command{
+	Use:   "path [from] [to]",
+	Short: "Show the shortest link chain between two notes",
+	Long: `Show the shortest chain of links from one note to another.
+
+Links are followed in their direction unless --undirected is given.`,
+	Args:    cobra.ExactArgs(2),
+	Aliases: []string{"p"},
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		graph := activeGraph(zettels)
+		var ends []string
+		for _, ref := range args {
+			i, err := internal.ResolveNote(zettels, ref)
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			if !graph.HasNode(zettels[i].NoteID) {
+				log.Printf("❌ Note [%s] %s is archived or deleted", zettels[i].ID, zettels[i].Title)
+				os.Exit(1)
+			}
+			ends = append(ends, zettels[i].NoteID)
+		}
+		if ends[0] == ends[1] {
+			log.Println("❌ Both notes are the same")
+			os.Exit(1)
+		}
+
+		path := graph.ShortestPath(ends[0], ends[1], !graphUndirected)
+		if path == nil {
+			log.Printf("⚠️ No link chain from %s to %s", graphNodeLabel(graph, ends[0]), graphNodeLabel(graph, ends[1]))
+			os.Exit(1)
+		}
+
+		if machineOutput() {
+			noteIDs := []string{ends[0]}
+			for _, edge := range path {
+				if edge.To != noteIDs[len(noteIDs)-1] {
+					noteIDs = append(noteIDs, edge.To)
+				} else {
+					noteIDs = append(noteIDs, edge.From)
+				}
+			}
+			records := []internal.NoteRecord{}
+			for _, noteID := range noteIDs {
+				for _, zettel := range zettels {
+					if zettel.NoteID == noteID {
+						records = append(records, internal.NewNoteRecord(zettel))
+						break
+					}
+				}
+			}
+			printRecords(records)
+			return
+		}
+
+		metaStyle := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("🧭 Shortest path (%d links):\n", len(path))
+		fmt.Printf("  %s\n", graphNodeLabel(graph, ends[0]))
+		at := ends[0]
+		for _, edge := range path {
+			arrow, next := "→", edge.To
+			if edge.From != at {
+				arrow, next = "←", edge.From
+			}
+			fmt.Printf("  %s %s  %s\n", arrow, graphNodeLabel(graph, next), metaStyle("("+strings.Join(edge.Sources, ", ")+")"))
+			at = next
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.AddCommand(graphExportCmd)
+	graphCmd.AddCommand(graphStatsCmd)
+	graphCmd.AddCommand(graphPathCmd)
+	graphExportCmd.Flags().StringVarP(&graphFormat, "format", "f", internal.GraphDOT, fmt.Sprintf("Export format (%s)", strings.Join(internal.GraphFormats, ", ")))
+	graphExportCmd.Flags().StringSliceVar(&graphTags, "tag", []string{}, "Only export notes with one of these tags")
+	graphExportCmd.Flags().StringVar(&graphAround, "around", "", "Only export the neighbourhood of this note")
+	graphExportCmd.Flags().IntVar(&graphDepth, "depth", 1, "Number of links to follow from --around")
+	graphExportCmd.Flags().StringSliceVar(&graphRels, "rel", []string{}, "Only export links with one of these relations ('none' for untyped links)")
+	graphExportCmd.Flags().StringVar(&graphOut, "out", "", "Write the graph to a file instead of stdout")
+	graphStatsCmd.Flags().IntVar(&graphTop, "top", 10, "Number of hubs and communities to show")
+	graphStatsCmd.Flags().StringVar(&graphHubsBy, "by", "pagerank", "Rank hubs by 'pagerank', 'in' or 'out' degree")
+	graphPathCmd.Flags().BoolVar(&graphUndirected, "undirected", false, "Follow links in either direction")
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var indexTitle string
+var indexType string
+var indexGroupBy string
+
+// Index sections of a note body
+func indexSpecs(body string) []internal.IndexSpec {
+	var specs []internal.IndexSpec
+	for _, key := range internal.ManagedSectionKeys(body) {
+		if spec, ok := internal.ParseIndexKey(key); ok {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// Find the note holding the index section of a spec
+func findIndexNote(zettels []internal.Zettel, spec internal.IndexSpec) *internal.Zettel {
+	for i := range zettels {
+		if zettels[i].Deleted {
+			continue
+		}
+		content, err := os.ReadFile(zettels[i].NotePath)
+		if err != nil {
+			continue
+		}
+		for _, key := range internal.ManagedSectionKeys(string(content)) {
+			if key == spec.Key() {
+				return &zettels[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Regenerate the index sections of a note, returning the number of listed notes
+func refreshIndexNote(note *internal.Zettel, specs []internal.IndexSpec, zettels []internal.Zettel, config internal.Config) (int, error) {
+	content, err := os.ReadFile(note.NotePath)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Failed to read note: %w", err)
+	}
+
+	frontMatter, body, err := internal.ParseFrontMatter(string(content))
+	if err != nil {
+		return 0, fmt.Errorf("❌ Failed to parse front matter: %w", err)
+	}
+
+	listedCount := 0
+	updatedBody := body
+	for _, spec := range specs {
+		matches, err := spec.Match(zettels, config)
+		if err != nil {
+			return 0, fmt.Errorf("❌ Invalid query %q: %w", spec.Source, err)
+		}
+
+		// The index note never lists itself
+		listed := []internal.Zettel{}
+		for _, match := range matches {
+			if match.NoteID != note.NoteID {
+				listed = append(listed, match)
+			}
+		}
+		listedCount += len(listed)
+		updatedBody = internal.ReplaceManagedSection(updatedBody, spec.Key(), spec.Format(listed))
+	}
+	if updatedBody == body {
+		return listedCount, nil
+	}
+
+	frontMatter.UpdatedAt = time.Now().Format(internal.TimestampLayout)
+	if err := os.WriteFile(note.NotePath, []byte(internal.UpdateFrontMatter(&frontMatter, updatedBody)), 0644); err != nil {
+		return 0, fmt.Errorf("❌ Failed to write note: %w", err)
+	}
+	note.UpdatedAt = frontMatter.UpdatedAt
+
+	return listedCount, nil
+}
+
+// Regenerate every index note
+func refreshIndexNotes(config internal.Config) error {
+	zettels, err := internal.LoadJson(config)
+	if err != nil {
+		return err
+	}
+
+	refreshed := 0
+	for i := range zettels {
+		if zettels[i].Deleted {
+			continue
+		}
+		content, err := os.ReadFile(zettels[i].NotePath)
+		if err != nil {
+			continue
+		}
+		specs := indexSpecs(string(content))
+		if len(specs) == 0 {
+			continue
+		}
+		count, err := refreshIndexNote(&zettels[i], specs, zettels, config)
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			continue
+		}
+		log.Printf("🔄 Refreshed index [%s] %s (%d notes)", zettels[i].ID, zettels[i].Title, count)
+		refreshed++
+	}
+
+	if refreshed == 0 {
+		return nil
+	}
+	return internal.SaveUpdatedJson(zettels, &config)
+}
+
+var indexCmd = &cobra.Command{
+	Use:     "index",
+	Short:   "Generate index and structure notes",
+	Aliases: []string{"ix"},
+}
+
+var indexBuildCmd = &cobra.Command{
+	Use:   "build [tag|query]",
+	Short: "Create or refresh an index note listing notes by tag or query",
+	Long: `Create or refresh an index note whose marker-delimited section lists the
+matching notes as links, grouped by type or by tag. Text outside the markers
+is left untouched.
+
+A single word is a tag (also matching its sub-tags, such as go/concurrency);
+anything else is a query:
+
+  zk index build go --group-by tag
+  zk index build 'type:permanent project:zk' --title "zk map" --type structure
+
+Index notes are regenerated by "zk index refresh" and "zk sync".`,
+	Args:    cobra.MinimumNArgs(1),
+	Aliases: []string{"b"},
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := internal.NewIndexSpec(strings.Join(args, " "), indexGroupBy)
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		if indexType != "index" && indexType != "structure" {
+			log.Printf("❌ Invalid note type %q: must be 'index' or 'structure'", indexType)
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		note := findIndexNote(zettels, spec)
+		if note == nil {
+			title := indexTitle
+			if title == "" {
+				title = fmt.Sprintf("Index: %s", spec.Source)
+			}
+			if _, _, err := createNewNote(title, indexType, []string{}, "", *config); err != nil {
+				log.Printf("❌ Failed to create index note: %v", err)
+				os.Exit(1)
+			}
+
+			// Reload to pick up the short ID given to the new note
+			if zettels, err = internal.LoadJson(*config); err != nil {
+				log.Printf("❌ Error loading JSON: %v", err)
+				os.Exit(1)
+			}
+			note = &zettels[len(zettels)-1]
+		}
+
+		count, err := refreshIndexNote(note, []internal.IndexSpec{spec}, zettels, *config)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
+		if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+			log.Printf("❌ Error updating JSON: %v", err)
+			os.Exit(1)
+		}
+
+		log.Printf("✅ Index [%s] %s lists %d notes", note.ID, note.Title, count)
+	},
+}
+
+var indexRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Regenerate all index notes",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		if err := refreshIndexNotes(*config); err != nil {
+			log.Printf("❌ Error refreshing index notes: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(indexCmd)
+	indexCmd.AddCommand(indexBuildCmd)
+	indexCmd.AddCommand(indexRefreshCmd)
+	indexBuildCmd.Flags().StringVar(&indexTitle, "title", "", "Title of a new index note")
+	indexBuildCmd.Flags().StringVarP(&indexType, "type", "t", "index", "Type of a new index note ('index' or 'structure')")
+	indexBuildCmd.Flags().StringVar(&indexGroupBy, "group-by", internal.IndexByType, "Group notes by 'type' or 'tag' (sub-tag for a tag index)")
+}
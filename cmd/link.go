@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/nakachan-ing/Zettelkasten-cli/internal"
@@ -19,28 +20,20 @@ import (
 var threshold float64
 var manualFlag bool
 var autoFlag bool
+var linkRel string
+var linkNote string
 
 // Update `links:` field in front matter
-func addLinkToFrontMatter(frontMatter *internal.FrontMatter, newLinks []string) *internal.FrontMatter {
-	if frontMatter.Links == nil {
-		frontMatter.Links = []string{}
-	}
-
-	for _, newLink := range newLinks {
-		exists := false
-		for _, existing := range frontMatter.Links {
-			if existing == newLink {
-				exists = true
-				break
-			}
-		}
-		if !exists {
-			frontMatter.Links = append(frontMatter.Links, newLink)
-		}
-	}
+func addLinkToFrontMatter(frontMatter *internal.FrontMatter, newLinks internal.Links) *internal.FrontMatter {
+	frontMatter.Links = internal.MergeLinks(frontMatter.Links, newLinks...)
 	return frontMatter
 }
 
+// Link to a note carrying the --rel and --note flags
+func typedLink(noteID string) internal.Link {
+	return internal.Link{To: noteID, Rel: internal.NormalizeRelation(linkRel), Note: strings.TrimSpace(linkNote)}
+}
+
 // Allow user to select related notes
 func selectRelatedNotes(relatedNotes []internal.Zettel) []string {
 	var selected []string
@@ -101,7 +94,10 @@ func autoLinkNotes(fromID string, threshold float64, config internal.Config, zet
 
 	for i := range zettels {
 		if zettels[i].NoteID == fileID {
-			zettels[i].Links = mergeUniqueLinks(zettels[i].Links, selectedIDs)
+			for _, selectedID := range selectedIDs {
+				zettels[i].Links = internal.MergeLinks(zettels[i].Links, typedLink(selectedID))
+			}
+			zettels[i].AutoLinks = mergeUniqueLinks(zettels[i].AutoLinks, selectedIDs)
 			break
 		}
 	}
@@ -116,7 +112,11 @@ func autoLinkNotes(fromID string, threshold float64, config internal.Config, zet
 		return fmt.Errorf("❌ Failed to parse front matter: %w", err)
 	}
 
-	updatedFrontMatter := addLinkToFrontMatter(&frontMatter, selectedIDs)
+	newLinks := internal.Links{}
+	for _, selectedID := range selectedIDs {
+		newLinks = append(newLinks, typedLink(selectedID))
+	}
+	updatedFrontMatter := addLinkToFrontMatter(&frontMatter, newLinks)
 	updatedContent := internal.UpdateFrontMatter(updatedFrontMatter, body)
 
 	err = os.WriteFile(filePath, []byte(updatedContent), 0644)
@@ -150,7 +150,10 @@ func mergeUniqueLinks(existingLinks, newLinks []string) []string {
 	return merged
 }
 
-func insertLinkInContext(filePath string, title string, fileName string, keywords []string) (string, error) {
+func insertLinkInContext(filePath string, destination internal.Zettel, keywords []string) (string, error) {
+	title := destination.Title
+	fileName := destination.NoteID + ".md"
+
 	// ✅ Read note content
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -173,38 +176,60 @@ func insertLinkInContext(filePath string, title string, fileName string, keyword
 		return text, nil
 	}
 
+	// ✅ Find plain-text mentions of the destination's title or aliases. Options
+	// are told apart by position, as a term may be mentioned twice on a line.
+	type choice struct {
+		mention   *internal.Mention
+		insertPos int // Insertion position after a keyword, -1 to append to `### Links`
+	}
+	var options []string
+	var choices []choice
+	for _, mention := range internal.FindMentions(text, internal.NoteNames(destination)) {
+		column := utf8.RuneCountInString(mention.Text[:mention.Span.Start]) + 1
+		options = append(options, fmt.Sprintf("Link mention \"%s\" (line %d:%d)", mention.Term, mention.Line, column))
+		choices = append(choices, choice{mention: &mention, insertPos: -1})
+	}
+
 	// ✅ Find keyword positions in the text
-	var keywordPositions []string
-	positionMap := make(map[string]int)
 	for _, keyword := range keywords {
 		re := regexp.MustCompile(fmt.Sprintf(`\b%s\b`, regexp.QuoteMeta(keyword)))
 		loc := re.FindStringIndex(body)
 		if loc != nil {
-			position := fmt.Sprintf("After \"%s\"", keyword)
-			keywordPositions = append(keywordPositions, position)
-			positionMap[position] = loc[1] // Insertion position
+			options = append(options, fmt.Sprintf("After \"%s\"", keyword))
+			choices = append(choices, choice{insertPos: loc[1]})
 		}
 	}
 
 	// ✅ Add an option to append to `### Links`
-	keywordPositions = append(keywordPositions, "Append to ### Links")
+	options = append(options, "Append to ### Links")
+	choices = append(choices, choice{insertPos: -1})
 
 	// ✅ Let the user select the insertion point
-	var selectedPosition string
+	var selected int
 	prompt := &survey.Select{
 		Message: "Select where to insert the link:",
-		Options: keywordPositions,
+		Options: options,
 	}
-	err = survey.AskOne(prompt, &selectedPosition, nil)
+	err = survey.AskOne(prompt, &selected, nil)
 	if err != nil {
 		return "", fmt.Errorf("❌ Failed to get user input: %w", err)
 	}
+	chosen := choices[selected]
+
+	// ✅ Turn the chosen mention into the link
+	if chosen.mention != nil {
+		updatedText, err := internal.LinkMentions(text, []internal.Mention{*chosen.mention}, title, destination.NoteID)
+		if err != nil {
+			return "", err
+		}
+		log.Printf("✅ Linked mention: %s", markdownLink)
+		return updatedText, nil
+	}
 
 	// ✅ Insert the link at the chosen position
 	inserted := false
-	if selectedPosition != "Append to ### Links" {
-		insertPos := positionMap[selectedPosition]
-		body = body[:insertPos] + " " + markdownLink + body[insertPos:]
+	if chosen.insertPos >= 0 {
+		body = body[:chosen.insertPos] + " " + markdownLink + body[chosen.insertPos:]
 		inserted = true
 		log.Printf("✅ Inserted link in context: %s", markdownLink)
 	}
@@ -232,7 +257,12 @@ Manual linking:
   zk link --manual <from> <to>
 
 Automatic linking:
-  zk link --auto <from>`,
+  zk link --auto <from>
+
+Typed links:
+  zk link --manual <from> <to> --rel supports --note "same result with a larger sample"
+
+Omitted notes are picked from an interactive fuzzy finder.`,
 	Args:    cobra.ArbitraryArgs,
 	Aliases: []string{"ln"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -243,17 +273,19 @@ Automatic linking:
 
 		// ✅ Handle manual linking
 		if manualFlag {
-			if len(args) != 2 {
-				return fmt.Errorf("❌ Usage: zk link --manual <from> <to>")
+			if len(args) > 2 {
+				return fmt.Errorf("❌ Usage: zk link --manual [from] [to]")
 			}
+			args = append(args, "", "")
 			return runManualLink(args[0], args[1])
 		}
 
 		// ✅ Handle automatic linking
 		if autoFlag {
-			if len(args) != 1 {
-				return fmt.Errorf("❌ Usage: zk link --auto <from>")
+			if len(args) > 1 {
+				return fmt.Errorf("❌ Usage: zk link --auto [from]")
 			}
+			args = append(args, "")
 			return runAutoLink(args[0])
 		}
 
@@ -273,6 +305,21 @@ func runManualLink(sourceId, destinationId string) error {
 		return fmt.Errorf("❌ Failed to load JSON: %w", err)
 	}
 
+	if sourceId == "" {
+		if sourceId, err = pickNoteID(zettels, "Link from"); err != nil || sourceId == "" {
+			return err
+		}
+	} else if sourceId, err = resolveNoteID(zettels, sourceId); err != nil {
+		return err
+	}
+	if destinationId == "" {
+		if destinationId, err = pickNoteID(zettels, "Link to"); err != nil || destinationId == "" {
+			return err
+		}
+	} else if destinationId, err = resolveNoteID(zettels, destinationId); err != nil {
+		return err
+	}
+
 	var sourceZettel, destinationZettel *internal.Zettel
 	for i := range zettels {
 		if zettels[i].ID == sourceId {
@@ -303,7 +350,7 @@ func runManualLink(sourceId, destinationId string) error {
 		return fmt.Errorf("❌ Failed to extract key phrases: %v", err)
 	}
 
-	updatedMarkdown, err := insertLinkInContext(filePath, destinationZettel.Title, destinationZettel.NoteID+".md", keyPhrases)
+	updatedMarkdown, err := insertLinkInContext(filePath, *destinationZettel, keyPhrases)
 	if err != nil {
 		return fmt.Errorf("❌ Failed to insert link into markdown: %v", err)
 	}
@@ -313,7 +360,7 @@ func runManualLink(sourceId, destinationId string) error {
 		return fmt.Errorf("❌ Failed to parse front matter: %v", err)
 	}
 
-	updatedFrontMatter := addLinkToFrontMatter(&frontMatter, []string{destinationZettel.NoteID})
+	updatedFrontMatter := addLinkToFrontMatter(&frontMatter, internal.Links{typedLink(destinationZettel.NoteID)})
 	finalMarkdown := internal.UpdateFrontMatter(updatedFrontMatter, body)
 
 	err = os.WriteFile(filePath, []byte(finalMarkdown), 0644)
@@ -321,10 +368,14 @@ func runManualLink(sourceId, destinationId string) error {
 		return fmt.Errorf("❌ Failed to write updated note: %w", err)
 	}
 
-	sourceZettel.Links = mergeUniqueLinks(sourceZettel.Links, []string{destinationZettel.NoteID})
+	sourceZettel.Links = internal.MergeLinks(sourceZettel.Links, typedLink(destinationZettel.NoteID))
 	internal.SaveUpdatedJson(zettels, config)
 
-	fmt.Printf("✅ Linked [%s] %s to [%s] %s\n", sourceZettel.NoteID, sourceZettel.Title, destinationZettel.NoteID, destinationZettel.Title)
+	relation := ""
+	if link := typedLink(destinationZettel.NoteID); link.Rel != "" {
+		relation = fmt.Sprintf(" (%s)", link.Rel)
+	}
+	fmt.Printf("✅ Linked [%s] %s to [%s] %s%s\n", sourceZettel.NoteID, sourceZettel.Title, destinationZettel.NoteID, destinationZettel.Title, relation)
 	return nil
 }
 
@@ -353,6 +404,14 @@ func runAutoLink(fromID string) error {
 		return fmt.Errorf("❌ Failed to load JSON file: %w", err)
 	}
 
+	if fromID == "" {
+		if fromID, err = pickNoteID(zettels, "Link from"); err != nil || fromID == "" {
+			return err
+		}
+	} else if fromID, err = resolveNoteID(zettels, fromID); err != nil {
+		return err
+	}
+
 	// ✅ Compute TF-IDF for note similarity
 	tfidfMap := internal.ComputeTFIDFForZettels(zettels)
 
@@ -370,4 +429,6 @@ func init() {
 	rootCmd.AddCommand(linkCmd)
 	linkCmd.Flags().BoolVarP(&manualFlag, "manual", "m", false, "手動でノートをリンク")
 	linkCmd.Flags().BoolVar(&autoFlag, "auto", false, "関連ノートを自動でリンク")
+	linkCmd.Flags().StringVar(&linkRel, "rel", "", "リンクの関係 (supports / contradicts / continues / source-of など)")
+	linkCmd.Flags().StringVar(&linkNote, "note", "", "リンクに付ける短い注釈")
 }
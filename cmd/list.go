@@ -16,16 +16,34 @@ import (
 
 var listTypes []string
 var noteTags []string
+var listTagMode string
 var trash bool
 var archive bool
 var pageSize int
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List notes",
+	Use:   "list [query]",
+	Short: "List notes",
+	Long: `List notes, optionally filtered by a query.
+
+Query syntax:
+  type:permanent tag:go -tag:draft project:zk created:>2025-01 links:0 "exact phrase"
+
+Terms are combined with AND unless separated by OR; prefix a term with - (or NOT)
+to exclude it and use parentheses to group terms.`,
 	Aliases: []string{"ls"},
 	Run: func(cmd *cobra.Command, args []string) {
+		query, err := internal.ParseQuery(strings.Join(args, " "))
+		if err != nil {
+			log.Printf("❌ Invalid query: %v", err)
+			os.Exit(1)
+		}
+		if err := internal.ValidateTagMode(listTagMode); err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+
 		config, err := internal.LoadConfig()
 		if err != nil {
 			log.Printf("❌ Error loading config: %v", err)
@@ -48,8 +66,16 @@ var listCmd = &cobra.Command{
 		}
 
 		filteredNotes := []table.Row{}
+		matchedNotes := []internal.Zettel{}
+		queryContext := internal.NewQueryContext(*config, time.Now())
+
+		// Tasks are listed by `zk task list` unless the query asks for them
+		candidates := zettels
+		if !trash && !archive {
+			candidates = internal.QueryScope(zettels, query, true, false)
+		}
 
-		for _, zettel := range zettels {
+		for _, zettel := range candidates {
 			// Apply filters
 			if trash {
 				if !zettel.Deleted {
@@ -60,55 +86,41 @@ var listCmd = &cobra.Command{
 					continue
 				}
 			} else {
-				if zettel.Deleted {
-					continue
-				}
-				if zettel.NoteType == "task" {
-					continue
-				}
-
 				// Type filter
 				typeSet := make(map[string]bool)
 				for _, listType := range listTypes {
 					typeSet[strings.ToLower(listType)] = true
 				}
 
-				// Tag filter
-				tagSet := make(map[string]bool)
-				for _, tag := range noteTags {
-					tagSet[strings.ToLower(tag)] = true
-				}
-
 				// If --type is specified but the note type does not match, skip
 				if len(typeSet) > 0 && !typeSet[strings.ToLower(zettel.NoteType)] {
 					continue
 				}
 
-				// If --tag is specified but no matching tags are found, skip
-				if len(tagSet) > 0 {
-					match := false
-					for _, noteTag := range zettel.Tags {
-						normalizedNoteTag := strings.ToLower(strings.TrimSpace(noteTag))
-						for filterTag := range tagSet {
-							if strings.Contains(normalizedNoteTag, filterTag) { // Partial match
-								match = true
-								break
-							}
-						}
-					}
-					if !match {
-						continue
-					}
+				// If --tag is specified but the note tags do not match, skip
+				if !internal.MatchTags(zettel.Tags, noteTags, listTagMode) {
+					continue
 				}
 			}
 
+			// Query filter
+			if query != nil && !query.Eval(queryContext, zettel) {
+				continue
+			}
+
 			// Append filtered notes
+			matchedNotes = append(matchedNotes, zettel)
 			filteredNotes = append(filteredNotes, table.Row{
 				zettel.ID, zettel.Title, zettel.NoteType, zettel.Tags,
 				zettel.CreatedAt, zettel.UpdatedAt, len(zettel.Links),
 			})
 		}
 
+		if machineOutput() {
+			printRecords(internal.NewNoteRecords(matchedNotes))
+			return
+		}
+
 		// Handle case where no notes match
 		if len(filteredNotes) == 0 {
 			fmt.Println("No matching notes found.")
@@ -204,7 +216,8 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 
 	listCmd.Flags().StringSliceVarP(&listTypes, "type", "t", []string{}, "Specify note type")
-	listCmd.Flags().StringSliceVar(&noteTags, "tag", []string{}, "Specify tags")
+	listCmd.Flags().StringSliceVar(&noteTags, "tag", []string{}, "Specify tags (also matching sub-tags; '=tag' for the tag alone)")
+	listCmd.Flags().StringVar(&listTagMode, "tag-mode", internal.TagModeAny, "Match any or all of the --tag values (any, all)")
 	listCmd.Flags().BoolVar(&trash, "trash", false, "Show deleted notes")
 	listCmd.Flags().BoolVar(&archive, "archive", false, "Show archived notes")
 	listCmd.Flags().IntVar(&pageSize, "limit", 20, "Set the number of notes to display per page (-1 for all)")
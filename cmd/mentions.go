@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var mentionsLink bool
+var mentionsAll bool
+
+// Label of a mention in the selection prompt, with the line and column of the term
+func mentionOption(source internal.Zettel, mention internal.Mention) string {
+	column := utf8.RuneCountInString(mention.Text[:mention.Span.Start]) + 1
+	return fmt.Sprintf("[%s] %s:%d:%d  %s", source.ID, source.Title, mention.Line, column, internal.FormatSnippet(mention.Text, []internal.MatchSpan{mention.Span}, 60, fmt.Sprint))
+}
+
+// Let the user choose which mentions to convert into links
+func selectMentions(found []internal.UnlinkedMentions) ([]internal.UnlinkedMentions, error) {
+	options := []string{}
+	type choice struct{ note, mention int }
+	choices := []choice{} // By option position
+	for n, result := range found {
+		for m, mention := range result.Mentions {
+			options = append(options, mentionOption(result.Source, mention))
+			choices = append(choices, choice{n, m})
+		}
+	}
+
+	var selected []int
+	prompt := &survey.MultiSelect{
+		Message: "Select mentions to link:",
+		Options: options,
+	}
+	if err := survey.AskOne(prompt, &selected, nil); err != nil {
+		return nil, fmt.Errorf("❌ Failed to get user input: %w", err)
+	}
+
+	chosen := make([]internal.UnlinkedMentions, len(found))
+	for _, option := range selected {
+		c := choices[option]
+		chosen[c.note].Source = found[c.note].Source
+		chosen[c.note].Mentions = append(chosen[c.note].Mentions, found[c.note].Mentions[c.mention])
+	}
+
+	result := []internal.UnlinkedMentions{}
+	for _, note := range chosen {
+		if len(note.Mentions) > 0 {
+			result = append(result, note)
+		}
+	}
+	return result, nil
+}
+
+// Convert mentions into links to the target and record the links in front matter and `zettel.json`
+func linkMentions(target internal.Zettel, chosen []internal.UnlinkedMentions, zettels []internal.Zettel, config internal.Config) (int, error) {
+	linked := 0
+	var err error
+	for _, result := range chosen {
+		if err = linkNoteMentions(target, result, zettels); err != nil {
+			break
+		}
+		log.Printf("✅ Linked %d mention(s) in [%s] %s", len(result.Mentions), result.Source.ID, result.Source.Title)
+		linked += len(result.Mentions)
+	}
+
+	// Notes already rewritten keep their index entries in step, even when a later one failed
+	if linked > 0 {
+		if saveErr := internal.SaveUpdatedJson(zettels, &config); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	return linked, err
+}
+
+// Link the mentions found in one note, in its file and its index entry
+func linkNoteMentions(target internal.Zettel, result internal.UnlinkedMentions, zettels []internal.Zettel) error {
+	content, err := os.ReadFile(result.Source.NotePath)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read note: %w", err)
+	}
+
+	updated, err := internal.LinkMentions(string(content), result.Mentions, target.Title, target.NoteID)
+	if err != nil {
+		return err
+	}
+
+	frontMatter, body, err := internal.ParseFrontMatter(updated)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse front matter: %w", err)
+	}
+	frontMatter.UpdatedAt = time.Now().Format(internal.TimestampLayout)
+	updatedFrontMatter := addLinkToFrontMatter(&frontMatter, internal.PlainLinks(target.NoteID))
+
+	if err := os.WriteFile(result.Source.NotePath, []byte(internal.UpdateFrontMatter(updatedFrontMatter, body)), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write updated note: %w", err)
+	}
+
+	for i := range zettels {
+		if zettels[i].NoteID == result.Source.NoteID {
+			zettels[i].Links = internal.MergeLinks(zettels[i].Links, internal.PlainLinks(target.NoteID)...)
+			zettels[i].UpdatedAt = frontMatter.UpdatedAt
+			break
+		}
+	}
+	return nil
+}
+
+var mentionsCmd = &cobra.Command{
+	Use:   "mentions [id]",
+	Short: "List notes mentioning a note without linking to it",
+	Long: `List notes whose body mentions the title or an alias of a note without linking to it.
+
+With --link, the chosen mentions are replaced by [Title](NoteID.md) links and the
+links are added to the front matter of the mentioning notes and to zettel.json.`,
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"mn"},
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		i, err := internal.ResolveNote(zettels, args[0])
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		target := zettels[i]
+
+		found := internal.FindUnlinkedMentions(target, zettels)
+
+		if !mentionsLink {
+			if machineOutput() {
+				records := []internal.MentionRecord{}
+				for _, result := range found {
+					for _, mention := range result.Mentions {
+						records = append(records, internal.MentionRecord{
+							Ref: fmt.Sprintf("%s:%d", result.Source.ID, mention.Line),
+							ID:  result.Source.ID, NoteID: result.Source.NoteID, Title: result.Source.Title,
+							Line: mention.Line, Term: mention.Term, Text: mention.Text,
+						})
+					}
+				}
+				printRecords(records)
+				return
+			}
+
+			if len(found) == 0 {
+				log.Printf("⚠️ No unlinked mentions of [%s] %s", target.ID, target.Title)
+				return
+			}
+
+			titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
+			metaStyle := color.New(color.FgHiBlack).SprintFunc()
+			matchStyle := color.New(color.FgHiYellow, color.Bold).SprintFunc()
+
+			fmt.Printf("\n🔗 Unlinked mentions of [%s] %s (%d notes):\n\n", target.ID, titleStyle(target.Title), len(found))
+			for _, result := range found {
+				fmt.Printf("📄 [%s] %s\n", result.Source.ID, titleStyle(result.Source.Title))
+				for _, mention := range result.Mentions {
+					fmt.Printf("  %s %s\n", metaStyle(fmt.Sprintf("%5d:", mention.Line)),
+						internal.FormatSnippet(mention.Text, []internal.MatchSpan{mention.Span}, internal.DefaultSnippetWidth, matchStyle))
+				}
+				fmt.Println()
+			}
+			return
+		}
+
+		if len(found) == 0 {
+			log.Printf("⚠️ No unlinked mentions of [%s] %s", target.ID, target.Title)
+			return
+		}
+
+		chosen := found
+		if !mentionsAll {
+			if !internal.IsInteractive() {
+				log.Println("❌ Choosing mentions requires a terminal; use --all to link every mention")
+				os.Exit(1)
+			}
+			chosen, err = selectMentions(found)
+			if err != nil {
+				log.Printf("%v", err)
+				os.Exit(1)
+			}
+		}
+
+		linked, err := linkMentions(target, chosen, zettels, *config)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+		if linked == 0 {
+			log.Println("⚠️ No mentions selected. No links added.")
+			return
+		}
+		log.Printf("✅ Linked %d mention(s) of [%s] %s", linked, target.ID, target.Title)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mentionsCmd)
+	mentionsCmd.Flags().BoolVar(&mentionsLink, "link", false, "Convert chosen mentions into links")
+	mentionsCmd.Flags().BoolVar(&mentionsAll, "all", false, "With --link, link every mention without asking")
+}
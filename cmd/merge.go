@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var mergeYes bool
+var mergeDryRun bool
+
+// Note whose links are redirected by a merge
+type redirectedNote struct {
+	index       int    // Index of the note in zettels
+	body        string // Rewritten body, without the front matter
+	links       internal.Links
+	linksMoved  bool // Front matter links were redirected
+	bodyChanges []internal.LineChange
+}
+
+// Redirect the links to `from` in every other active note to `to`
+func planRedirects(zettels []internal.Zettel, from, to internal.Zettel) ([]redirectedNote, error) {
+	var planned []redirectedNote
+	for i, z := range zettels {
+		if z.NoteID == from.NoteID || z.NoteID == to.NoteID || z.Deleted {
+			continue
+		}
+		content, err := os.ReadFile(z.NotePath)
+		if err != nil {
+			continue
+		}
+		updated, changes := internal.RedirectLinks(string(content), from, to)
+		frontMatter, body, err := internal.ParseFrontMatter(updated)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to parse front matter of %s: %w", z.NotePath, err)
+		}
+		links, moved := internal.RedirectFrontMatterLinks(frontMatter.Links, from, to, z)
+		if len(changes) == 0 && !moved {
+			continue
+		}
+		planned = append(planned, redirectedNote{index: i, body: body, links: links, linksMoved: moved, bodyChanges: changes})
+	}
+	return planned, nil
+}
+
+// Move a note to the trash, like `zk delete`
+func trashNote(zettel *internal.Zettel, config internal.Config) error {
+	deletedPath := filepath.Join(config.Trash.TrashDir, zettel.NoteID+".md")
+
+	note, err := os.ReadFile(zettel.NotePath)
+	if err != nil {
+		return fmt.Errorf("❌ Error reading note file: %w", err)
+	}
+	frontMatter, body, err := internal.ParseFrontMatter(string(note))
+	if err != nil {
+		return fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+	updatedContent := internal.UpdateFrontMatter(updateDeletedToFrontMatter(&frontMatter), body)
+	if err := os.WriteFile(zettel.NotePath, []byte(updatedContent), 0644); err != nil {
+		return fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+	if err := os.Rename(zettel.NotePath, deletedPath); err != nil {
+		return fmt.Errorf("❌ Error moving note to trash: %w", err)
+	}
+
+	zettel.NotePath = deletedPath
+	zettel.Deleted = true
+	return nil
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge [a] [b]",
+	Short: "Merge a note into another",
+	Long: `Merge note b into note a: b's body is appended to a's, their tags and links
+are combined and b's title becomes an alias of a. Every link to b across the
+vault (front matter, [Title](NoteID.md) and [[Title]] links) is pointed at a,
+then b is moved to the trash.
+
+The changed files are previewed before anything is written.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		ai, err := internal.ResolveNote(zettels, args[0])
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		bi, err := internal.ResolveNote(zettels, args[1])
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		a, b := zettels[ai], zettels[bi]
+		if a.NoteID == b.NoteID {
+			log.Println("❌ Cannot merge a note into itself")
+			os.Exit(1)
+		}
+		for _, z := range []internal.Zettel{a, b} {
+			if z.Deleted {
+				log.Printf("❌ Note [%s] %s is in the trash", z.ID, z.Title)
+				os.Exit(1)
+			}
+		}
+
+		bContent, err := os.ReadFile(b.NotePath)
+		if err != nil {
+			log.Printf("❌ Failed to read note: %v", err)
+			os.Exit(1)
+		}
+		bFrontMatter, bBody, err := internal.ParseFrontMatter(string(bContent))
+		if err != nil {
+			log.Printf("❌ Failed to parse front matter: %v", err)
+			os.Exit(1)
+		}
+		// Links between the two notes would point at the merged note itself
+		bBody = internal.UnlinkNote(internal.UnlinkNote(bBody, a), b)
+
+		planned, err := planRedirects(zettels, b, a)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
+		// Preview
+		noteStyle := color.New(color.FgHiCyan, color.Bold).SprintFunc()
+		added := color.New(color.FgGreen).SprintFunc()
+		removed := color.New(color.FgRed).SprintFunc()
+		fmt.Printf("%s\n", noteStyle(fmt.Sprintf("[%s] %s", a.ID, a.NotePath)))
+		fmt.Printf("  %s\n", added(fmt.Sprintf("+ %d lines from [%s] %s", len(strings.Split(bBody, "\n")), b.ID, b.Title)))
+		if newTags := internal.MergeTags(a.Tags, b.Tags...); len(newTags) > len(a.Tags) {
+			fmt.Printf("  %s\n", added("+ tags: "+strings.Join(newTags[len(a.Tags):], ", ")))
+		}
+		if len(b.Links) > 0 {
+			fmt.Printf("  %s\n", added(fmt.Sprintf("+ links: %s", strings.Join(b.Links.Targets(), ", "))))
+		}
+		fmt.Printf("  %s\n", added("+ aliases: "+b.Title))
+		for _, note := range planned {
+			z := zettels[note.index]
+			fmt.Printf("%s\n", noteStyle(fmt.Sprintf("[%s] %s", z.ID, z.NotePath)))
+			if note.linksMoved {
+				fmt.Printf("  %s\n", added(fmt.Sprintf("~ links: %s → %s", b.NoteID, a.NoteID)))
+			}
+			printLineChanges(note.bodyChanges)
+		}
+		fmt.Printf("%s\n  %s\n", noteStyle(fmt.Sprintf("[%s] %s", b.ID, b.NotePath)), removed("- moved to the trash"))
+
+		if mergeDryRun {
+			return
+		}
+		if !mergeYes {
+			if !internal.IsInteractive() {
+				log.Println("❌ Confirming the merge requires a terminal; use --yes to apply it")
+				os.Exit(1)
+			}
+			confirmed, err := confirmChanges()
+			if err != nil {
+				log.Printf("%v", err)
+				os.Exit(1)
+			}
+			if !confirmed {
+				log.Println("⚠️ Merge cancelled")
+				return
+			}
+		}
+
+		if err := writeNoteBody(&zettels[ai], func(frontMatter *internal.FrontMatter, body string) string {
+			frontMatter.Tags = internal.MergeTags(frontMatter.Tags, bFrontMatter.Tags...)
+			frontMatter.Links, _ = internal.RedirectFrontMatterLinks(internal.MergeLinks(frontMatter.Links, bFrontMatter.Links...), b, a, a)
+			frontMatter.Aliases = internal.MergeAliases(frontMatter.Aliases, frontMatter.Title, append([]string{b.Title}, bFrontMatter.Aliases...)...)
+			body = internal.UnlinkNote(body, b)
+			return strings.TrimSpace(body) + "\n\n" + bBody
+		}); err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
+		for _, note := range planned {
+			if err := writeNoteBody(&zettels[note.index], func(frontMatter *internal.FrontMatter, _ string) string {
+				frontMatter.Links = note.links
+				return note.body
+			}); err != nil {
+				log.Printf("%v", err)
+			}
+		}
+
+		// Links recorded by `zk link --auto` follow their front matter link (b's
+		// move to a), except those that would point at the note carrying them
+		zettels[ai].AutoLinks = append(zettels[ai].AutoLinks, b.AutoLinks...)
+		for i := range zettels {
+			if zettels[i].NoteID == b.NoteID {
+				continue
+			}
+			autoLinks := []string{}
+			for _, link := range zettels[i].AutoLinks {
+				if link == b.NoteID || link == b.ID {
+					link = a.NoteID
+				}
+				if link == zettels[i].NoteID || link == zettels[i].ID || contains(autoLinks, link) {
+					continue
+				}
+				autoLinks = append(autoLinks, link)
+			}
+			if len(zettels[i].AutoLinks) > 0 {
+				zettels[i].AutoLinks = autoLinks
+			}
+		}
+
+		if err := trashNote(&zettels[bi], *config); err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
+		if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+			log.Printf("❌ Error updating JSON: %v", err)
+			os.Exit(1)
+		}
+
+		log.Printf("✅ Merged [%s] %s into [%s] %s (%d linking notes updated)", b.ID, b.Title, a.ID, a.Title, len(planned))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().BoolVarP(&mergeYes, "yes", "y", false, "Apply the merge without asking")
+	mergeCmd.Flags().BoolVar(&mergeDryRun, "dry-run", false, "Only preview the changes")
+}
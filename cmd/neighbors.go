@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var neighborsDepth int
+var neighborsDirection string
+
+// Print the link tree around a note: → marks links (with their relation), ← backlinks, ↻ cycles
+func printNeighbourTree(graph internal.Graph, noteID string, depth int, direction string) {
+	titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
+	typeStyle := color.New(color.FgHiGreen).SprintFunc()
+	metaStyle := color.New(color.FgHiBlack).SprintFunc()
+	cycleStyle := color.New(color.FgHiYellow).SprintFunc()
+	relStyle := color.New(color.FgHiMagenta).SprintFunc()
+
+	for i, line := range internal.NeighbourTree(graph, noteID, depth, direction) {
+		label := fmt.Sprintf("[%s] %s", line.Node.ID, line.Node.Title)
+		if line.Node.Type != "" {
+			label += " " + typeStyle("("+line.Node.Type+")")
+		}
+		if i == 0 {
+			fmt.Println(titleStyle(label))
+			continue
+		}
+
+		arrow := "→"
+		if line.Incoming {
+			arrow = "←"
+		}
+		if line.Edge.Rel != "" {
+			arrow += " " + relStyle(line.Edge.Rel)
+		}
+		switch {
+		case line.Cycle:
+			label += " " + cycleStyle("↻ cycle")
+		case line.Repeated:
+			label += " " + metaStyle("(see above)")
+		}
+		if sources := line.Edge.Sources; len(sources) == 1 && sources[0] != internal.EdgeFrontMatter {
+			label += " " + metaStyle("["+sources[0]+"]")
+		}
+		fmt.Printf("%s%s %s\n", metaStyle(line.Prefix), arrow, label)
+	}
+}
+
+// Check the --depth and --direction flags of the neighbourhood view
+func validateNeighbourFlags() error {
+	if neighborsDepth < 1 {
+		return fmt.Errorf("--depth must be at least 1")
+	}
+	return internal.ValidateDirection(strings.ToLower(neighborsDirection))
+}
+
+var neighborsCmd = &cobra.Command{
+	Use:   "neighbors [id]",
+	Short: "Show the link tree around a note",
+	Long: `Show the notes linked from (→) and linking to (←) a note as a tree, following
+links up to --depth levels. Notes leading back to an ancestor are marked as
+cycles (↻) and notes already shown are not expanded again.
+
+Without an ID, pick the note from an interactive fuzzy finder.`,
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"nb", "neighbours"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateNeighbourFlags(); err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		var target internal.Zettel
+		if len(args) == 0 {
+			picked, err := pickActiveNote(zettels, "Neighbors of")
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			if picked == nil {
+				return
+			}
+			target = *picked
+		} else {
+			i, err := internal.ResolveNote(zettels, args[0])
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			target = zettels[i]
+		}
+
+		graph := activeGraph(zettels)
+		if !graph.HasNode(target.NoteID) {
+			log.Printf("❌ Note [%s] %s is archived or deleted", target.ID, target.Title)
+			os.Exit(1)
+		}
+		if machineOutput() {
+			printRecords(internal.NewNeighbourRecords(internal.NeighbourTree(graph, target.NoteID, neighborsDepth, strings.ToLower(neighborsDirection))))
+			return
+		}
+		printNeighbourTree(graph, target.NoteID, neighborsDepth, strings.ToLower(neighborsDirection))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(neighborsCmd)
+	neighborsCmd.Flags().IntVar(&neighborsDepth, "depth", 2, "Number of link levels to follow")
+	neighborsCmd.Flags().StringVar(&neighborsDirection, "direction", internal.DirectionBoth, "Links to follow: 'both', 'out' (links) or 'in' (backlinks)")
+}
@@ -15,6 +15,8 @@ import (
 
 var noteType string
 var tags []string
+var newAfter string
+var newBranch string
 
 var validTypes = map[string]bool{
 	"fleeting":   true,
@@ -31,7 +33,7 @@ func validateNoteType(noteType string) error {
 	return nil
 }
 
-func createNewNote(title, noteType string, tags []string, config internal.Config) (string, internal.Zettel, error) {
+func createNewNote(title, noteType string, tags []string, sequence string, config internal.Config) (string, internal.Zettel, error) {
 	t := time.Now()
 	noteId := fmt.Sprintf("%d%02d%02d%02d%02d%02d",
 		t.Year(), t.Month(), t.Day(),
@@ -46,6 +48,7 @@ func createNewNote(title, noteType string, tags []string, config internal.Config
 		Title:     title,
 		Type:      noteType,
 		Tags:      tags,
+		Sequence:  sequence,
 		CreatedAt: createdAt,
 		UpdatedAt: createdAt,
 	}
@@ -73,6 +76,7 @@ func createNewNote(title, noteType string, tags []string, config internal.Config
 		NoteType:  noteType,
 		Title:     title,
 		Tags:      tags,
+		Sequence:  sequence,
 		CreatedAt: createdAt,
 		UpdatedAt: createdAt,
 		NotePath:  filePath,
@@ -80,6 +84,7 @@ func createNewNote(title, noteType string, tags []string, config internal.Config
 
 	err = internal.InsertZettelToJson(zettel, config)
 	if err != nil {
+		os.Remove(filePath)
 		return "", internal.Zettel{}, fmt.Errorf("failed to write to JSON file: %w", err)
 	}
 
@@ -89,8 +94,12 @@ func createNewNote(title, noteType string, tags []string, config internal.Config
 
 // `newCmd` represents the new command
 var newCmd = &cobra.Command{
-	Use:     "new [title]",
-	Short:   "Create a new note",
+	Use:   "new [title]",
+	Short: "Create a new note",
+	Long: `Create a new note.
+
+With --after or --branch, the note gets the next Folgezettel sequence position
+continuing a note's train (1a → 1b) or branching off it (1a → 1a1).`,
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"n"},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -115,8 +124,22 @@ var newCmd = &cobra.Command{
 			log.Printf("⚠️ Trash cleanup failed: %v", err)
 		}
 
+		// Assign the next sequence position
+		sequence := ""
+		if newAfter != "" && newBranch != "" {
+			log.Println("❌ Cannot use both `--after` and `--branch` at the same time")
+			os.Exit(1)
+		}
+		if newAfter != "" || newBranch != "" {
+			sequence, err = nextSequence(newAfter, newBranch, *config)
+			if err != nil {
+				log.Printf("%v", err)
+				os.Exit(1)
+			}
+		}
+
 		// Create a new note
-		newZettelStr, newZettel, err := createNewNote(title, noteType, tags, *config)
+		newZettelStr, newZettel, err := createNewNote(title, noteType, tags, sequence, *config)
 		if err != nil {
 			log.Printf("❌ Failed to create note: %v", err)
 			os.Exit(1)
@@ -164,12 +187,7 @@ var newCmd = &cobra.Command{
 				}
 
 				// Update note metadata
-				zettels[i].Title = frontMatter.Title
-				zettels[i].NoteType = frontMatter.Type
-				zettels[i].Tags = frontMatter.Tags
-				zettels[i].Links = frontMatter.Links
-				zettels[i].TaskStatus = frontMatter.TaskStatus
-				zettels[i].UpdatedAt = frontMatter.UpdatedAt
+				zettels[i].CopyFrontMatter(frontMatter)
 
 				// Convert to JSON
 				updatedJson, err := json.MarshalIndent(zettels, "", "  ")
@@ -200,4 +218,6 @@ func init() {
 	rootCmd.AddCommand(newCmd)
 	newCmd.Flags().StringVarP(&noteType, "type", "t", "fleeting", "Specify new note type")
 	newCmd.Flags().StringSliceVar(&tags, "tag", []string{}, "Specify tags")
+	newCmd.Flags().StringVar(&newAfter, "after", "", "Continue the sequence of this note (1a → 1b)")
+	newCmd.Flags().StringVar(&newBranch, "branch", "", "Branch off the sequence of this note (1a → 1a1)")
 }
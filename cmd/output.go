@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+)
+
+// Check whether `--output` asks for machine-readable output
+func machineOutput() bool {
+	return internal.IsMachineOutput(outputFormat)
+}
+
+// Write records to stdout in the `--output` format
+func printRecords[T internal.OutputRecord](records []T) {
+	if err := internal.WriteRecords(os.Stdout, outputFormat, records); err != nil {
+		log.Printf("❌ Error writing output: %v", err)
+		os.Exit(1)
+	}
+}
+
+// Write a single record to stdout in the `--output` format
+func printRecord[T internal.OutputRecord](record T) {
+	if err := internal.WriteRecord(os.Stdout, outputFormat, record); err != nil {
+		log.Printf("❌ Error writing output: %v", err)
+		os.Exit(1)
+	}
+}
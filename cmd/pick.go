@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+)
+
+// Let the user pick one of the active notes (nil when cancelled)
+func pickActiveNote(zettels []internal.Zettel, prompt string) (*internal.Zettel, error) {
+	active := []internal.Zettel{}
+	for _, zettel := range zettels {
+		if !zettel.Deleted && !zettel.Archived {
+			active = append(active, zettel)
+		}
+	}
+	return internal.PickNote(internal.PickerItems(active), prompt)
+}
+
+// Let the user pick an active note and return its short ID ("" when cancelled)
+func pickNoteID(zettels []internal.Zettel, prompt string) (string, error) {
+	picked, err := pickActiveNote(zettels, prompt)
+	if err != nil {
+		return "", fmt.Errorf("❌ %w", err)
+	}
+	if picked == nil {
+		return "", nil
+	}
+	return picked.ID, nil
+}
+
+// Resolve a note reference (short ID, note ID, title or title fragment) to a short ID
+func resolveNoteID(zettels []internal.Zettel, ref string) (string, error) {
+	i, err := internal.ResolveNote(zettels, ref)
+	if err != nil {
+		return "", fmt.Errorf("❌ %w", err)
+	}
+	return zettels[i].ID, nil
+}
+
+// Resolve a reference to a note in the trash or the archive to its short ID
+func resolveRestorableNoteID(zettels []internal.Zettel, ref string) (string, error) {
+	i, err := internal.ResolveNoteAmong(zettels, ref, func(z internal.Zettel) bool { return z.Deleted || z.Archived })
+	if err != nil {
+		return "", fmt.Errorf("❌ %w", err)
+	}
+	return zettels[i].ID, nil
+}
@@ -8,14 +8,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/text"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/nakachan-ing/Zettelkasten-cli/internal"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
 func createNewProject(projectName string, tags []string, config internal.Config) (string, internal.Zettel, error) {
-	tagName := strings.ReplaceAll(projectName, " ", "_")
-	tags = append(tags, fmt.Sprintf("project:%v", tagName))
+	tags = append(tags, internal.ProjectTag(projectName))
 
 	t := time.Now()
 	noteId := fmt.Sprintf("%d%02d%02d%02d%02d%02d",
@@ -111,7 +113,6 @@ var projectAddCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(2),
 	Aliases: []string{"a"},
 	Run: func(cmd *cobra.Command, args []string) {
-		noteID := args[0]
 		projectName := args[1]
 
 		config, err := internal.LoadConfig()
@@ -126,6 +127,12 @@ var projectAddCmd = &cobra.Command{
 			return
 		}
 
+		noteID, err := resolveNoteID(zettels, args[0])
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
 		for i := range zettels {
 			if noteID == zettels[i].ID {
 				noteByte, err := os.ReadFile(zettels[i].NotePath)
@@ -140,7 +147,7 @@ var projectAddCmd = &cobra.Command{
 					return
 				}
 
-				projectTag := fmt.Sprintf("project:%s", strings.ReplaceAll(projectName, " ", "_"))
+				projectTag := internal.ProjectTag(projectName)
 				if !contains(frontMatter.Tags, projectTag) {
 					frontMatter.Tags = append(frontMatter.Tags, projectTag)
 				}
@@ -166,6 +173,220 @@ var projectAddCmd = &cobra.Command{
 	},
 }
 
+// Render a progress bar such as `██████░░░░ 60%`
+func progressBar(percent, width int) string {
+	filled := percent * width / 100
+	return fmt.Sprintf("%s%s %3d%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), percent)
+}
+
+var projectShowCmd = &cobra.Command{
+	Use:     "show [project]",
+	Short:   "Show a project dashboard",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"s"},
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := args[0]
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		summary := internal.SummarizeProject(zettels, projectName, *config, time.Now(), 5)
+		if len(summary.Notes) == 0 && len(summary.Tasks) == 0 {
+			log.Printf("❌ Project %s not found", projectName)
+			os.Exit(1)
+		}
+
+		if machineOutput() {
+			printRecord(internal.NewProjectRecord(summary))
+			return
+		}
+
+		titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
+		sectionStyle := color.New(color.FgHiGreen, color.Bold).SprintFunc()
+
+		fmt.Printf("📁 %v\n", titleStyle(summary.Name))
+		fmt.Println(strings.Repeat("-", 50))
+		fmt.Printf("Notes: %d  Tasks: %d  Progress: %s\n", len(summary.Notes), len(summary.Tasks), progressBar(summary.Progress(), 20))
+		if summary.ChecklistTotal > 0 {
+			fmt.Printf("Checklist: %s\n", internal.FormatProgress(summary.ChecklistDone, summary.ChecklistTotal))
+		}
+
+		// Task counts by status, in workflow order
+		if len(summary.Tasks) > 0 {
+			fmt.Printf("\n%v\n", sectionStyle("Tasks by status"))
+			for _, status := range config.TaskStatuses() {
+				fmt.Printf("  %-12s %d\n", status, summary.StatusCount[status])
+			}
+		}
+
+		// Overdue tasks
+		if len(summary.Overdue) > 0 {
+			fmt.Printf("\n%v\n", sectionStyle("Overdue"))
+			for _, task := range summary.Overdue {
+				fmt.Printf("  ⏰ [%s] %s (due %s, %s)\n", task.ID, task.Title, text.FgHiRed.Sprint(task.Due), task.TaskStatus)
+			}
+		}
+
+		// Notes
+		if len(summary.Notes) > 0 {
+			fmt.Printf("\n%v\n", sectionStyle("Notes"))
+			for _, note := range summary.Notes {
+				fmt.Printf("  📄 [%s] %s (%s)\n", note.ID, note.Title, note.NoteType)
+			}
+		}
+
+		// Recent activity
+		fmt.Printf("\n%v\n", sectionStyle("Recent activity"))
+		for _, zettel := range summary.Recent {
+			fmt.Printf("  %s  [%s] %s\n", zettel.UpdatedAt, zettel.ID, zettel.Title)
+		}
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List projects with their progress",
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		projects := internal.ListProjects(zettels)
+		now := time.Now()
+		if machineOutput() {
+			records := []internal.ProjectRecord{}
+			for _, project := range projects {
+				records = append(records, internal.NewProjectRecord(internal.SummarizeProject(zettels, project, *config, now, 0)))
+			}
+			printRecords(records)
+			return
+		}
+
+		if len(projects) == 0 {
+			log.Println("⚠️ No projects found.")
+			return
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleDouble)
+		t.Style().Options.SeparateRows = false
+		t.AppendHeader(table.Row{"Project", "Notes", "Tasks", "Done", "Overdue", "Progress"})
+
+		for _, project := range projects {
+			summary := internal.SummarizeProject(zettels, project, *config, now, 0)
+			t.AppendRow(table.Row{
+				project, len(summary.Notes), len(summary.Tasks), summary.Done,
+				len(summary.Overdue), progressBar(summary.Progress(), 10),
+			})
+		}
+		t.Render()
+	},
+}
+
+var projectRenameCmd = &cobra.Command{
+	Use:   "rename [old] [new]",
+	Short: "Rename a project across all notes",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		oldName := args[0]
+		newName := args[1]
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			return
+		}
+
+		updated := 0
+		for i := range zettels {
+			if !internal.InProject(zettels[i], oldName) {
+				continue
+			}
+
+			noteByte, err := os.ReadFile(zettels[i].NotePath)
+			if err != nil {
+				log.Printf("❌ Error reading note file: %v", err)
+				continue
+			}
+
+			frontMatter, body, err := internal.ParseFrontMatter(string(noteByte))
+			if err != nil {
+				log.Printf("❌ Error parsing front matter: %s (%v)", zettels[i].NotePath, err)
+				continue
+			}
+
+			frontMatter.Tags, _ = internal.RenameProjectTag(frontMatter.Tags, oldName, newName)
+			frontMatter.UpdatedAt = time.Now().Format(internal.TimestampLayout)
+			updatedMarkdown := internal.UpdateFrontMatter(&frontMatter, body)
+
+			if err := os.WriteFile(zettels[i].NotePath, []byte(updatedMarkdown), 0644); err != nil {
+				log.Printf("❌ Error writing updated note: %v", err)
+				continue
+			}
+
+			zettels[i].Tags, _ = internal.RenameProjectTag(zettels[i].Tags, oldName, newName)
+			zettels[i].UpdatedAt = frontMatter.UpdatedAt
+			updated++
+		}
+
+		if updated == 0 {
+			log.Printf("⚠️ Project %s not found", oldName)
+			return
+		}
+
+		// The project note follows, with the links to it, as with `zk rename`
+		for i := range zettels {
+			z := zettels[i]
+			if z.Deleted || z.NoteType != "project" || !internal.InProject(z, newName) ||
+				!strings.EqualFold(internal.ProjectTag(z.Title), internal.ProjectTag(oldName)) {
+				continue
+			}
+			planned, err := planRenameLinks(zettels, z, newName)
+			if err != nil {
+				log.Printf("%v", err)
+				continue
+			}
+			if err := applyRename(zettels, i, newName, planned); err != nil {
+				log.Printf("%v", err)
+				continue
+			}
+			log.Printf("✅ Retitled [%s] %s to %s (%d linking notes updated)", z.ID, z.Title, newName, len(planned))
+		}
+
+		if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+			log.Printf("❌ Error updating JSON file: %v", err)
+			return
+		}
+
+		log.Printf("✅ Project %s renamed to %s (%d notes updated)", oldName, newName, updated)
+	},
+}
+
 func contains(slice []string, item string) bool {
 	for _, val := range slice {
 		if val == item {
@@ -178,5 +399,8 @@ func contains(slice []string, item string) bool {
 func init() {
 	projectCmd.AddCommand(projectNewCmd)
 	projectCmd.AddCommand(projectAddCmd)
+	projectCmd.AddCommand(projectShowCmd)
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectRenameCmd)
 	rootCmd.AddCommand(projectCmd)
 }
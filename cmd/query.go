@@ -0,0 +1,393 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var queryNoteTitle string
+
+// Regenerate the index note of a saved query, returning the number of listed notes
+func refreshMaterializedQuery(saved internal.SavedQuery, zettels []internal.Zettel, config internal.Config) (int, error) {
+	var note *internal.Zettel
+	for i := range zettels {
+		if zettels[i].NoteID == saved.NoteID {
+			note = &zettels[i]
+			break
+		}
+	}
+	if note == nil {
+		return 0, fmt.Errorf("❌ Index note %s of query %q not found", saved.NoteID, saved.Name)
+	}
+
+	matches, err := internal.RunQuery(zettels, saved.Query, config)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Invalid query %q: %w", saved.Name, err)
+	}
+
+	// The index note never lists itself
+	listed := []internal.Zettel{}
+	for _, match := range matches {
+		if match.NoteID != note.NoteID {
+			listed = append(listed, match)
+		}
+	}
+
+	content, err := os.ReadFile(note.NotePath)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Failed to read note: %w", err)
+	}
+
+	frontMatter, body, err := internal.ParseFrontMatter(string(content))
+	if err != nil {
+		return 0, fmt.Errorf("❌ Failed to parse front matter: %w", err)
+	}
+
+	updatedBody := internal.ReplaceManagedSection(body, "query "+saved.Name, internal.FormatLinkList(listed))
+	if updatedBody == body {
+		return len(listed), nil
+	}
+
+	frontMatter.UpdatedAt = time.Now().Format(internal.TimestampLayout)
+	if err := os.WriteFile(note.NotePath, []byte(internal.UpdateFrontMatter(&frontMatter, updatedBody)), 0644); err != nil {
+		return 0, fmt.Errorf("❌ Failed to write note: %w", err)
+	}
+	note.UpdatedAt = frontMatter.UpdatedAt
+
+	return len(listed), nil
+}
+
+// Regenerate every materialised saved query
+func refreshMaterializedQueries(config internal.Config) error {
+	queries, err := internal.LoadSavedQueries(config)
+	if err != nil {
+		return err
+	}
+
+	zettels, err := internal.LoadJson(config)
+	if err != nil {
+		return err
+	}
+
+	refreshed := 0
+	for _, saved := range queries {
+		if saved.NoteID == "" {
+			continue
+		}
+		count, err := refreshMaterializedQuery(saved, zettels, config)
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			continue
+		}
+		log.Printf("🔄 Refreshed query %q (%d notes)", saved.Name, count)
+		refreshed++
+	}
+
+	if refreshed == 0 {
+		return nil
+	}
+	return internal.SaveUpdatedJson(zettels, &config)
+}
+
+// Print notes as a table
+func renderNoteTable(zettels []internal.Zettel) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleDouble)
+	t.Style().Options.SeparateRows = false
+	t.AppendHeader(table.Row{"ID", "Title", "Type", "Tags", "Updated", "Links"})
+	for _, zettel := range zettels {
+		t.AppendRow(table.Row{zettel.ID, zettel.Title, zettel.NoteType, zettel.Tags, zettel.UpdatedAt, len(zettel.Links)})
+	}
+	t.Render()
+}
+
+var queryCmd = &cobra.Command{
+	Use:     "query",
+	Short:   "Manage saved searches",
+	Aliases: []string{"q"},
+}
+
+var querySaveCmd = &cobra.Command{
+	Use:   "save [name] [query]",
+	Short: "Save a named query",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		queryString := strings.Join(args[1:], " ")
+
+		if _, err := internal.ParseQuery(queryString); err != nil {
+			log.Printf("❌ Invalid query: %v", err)
+			return
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return
+		}
+
+		queries, err := internal.LoadSavedQueries(*config)
+		if err != nil {
+			log.Printf("❌ Error loading saved queries: %v", err)
+			return
+		}
+
+		if i := internal.FindSavedQuery(queries, name); i >= 0 {
+			queries[i].Query = queryString
+		} else {
+			queries = append(queries, internal.SavedQuery{Name: name, Query: queryString})
+		}
+
+		if err := internal.SaveSavedQueries(queries, *config); err != nil {
+			log.Printf("❌ Error saving queries: %v", err)
+			return
+		}
+
+		log.Printf("✅ Query %q saved: %s", name, queryString)
+	},
+}
+
+var queryRunCmd = &cobra.Command{
+	Use:   "run [name]",
+	Short: "Run a saved query",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		queries, err := internal.LoadSavedQueries(*config)
+		if err != nil {
+			log.Printf("❌ Error loading saved queries: %v", err)
+			os.Exit(1)
+		}
+
+		i := internal.FindSavedQuery(queries, name)
+		if i < 0 {
+			log.Printf("❌ Query %q not found", name)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		// Keep the materialised index note in step with the results
+		if queries[i].NoteID != "" {
+			if _, err := refreshMaterializedQuery(queries[i], zettels, *config); err != nil {
+				log.Printf("⚠️ %v", err)
+			} else if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+				log.Printf("❌ Error updating JSON: %v", err)
+			}
+		}
+
+		matches, err := internal.RunQuery(zettels, queries[i].Query, *config)
+		if err != nil {
+			log.Printf("❌ Invalid query: %v", err)
+			os.Exit(1)
+		}
+
+		if machineOutput() {
+			printRecords(internal.NewNoteRecords(matches))
+			return
+		}
+
+		if len(matches) == 0 {
+			log.Println("⚠️ No matching notes found.")
+			return
+		}
+
+		renderNoteTable(matches)
+	},
+}
+
+var queryListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List saved queries",
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		queries, err := internal.LoadSavedQueries(*config)
+		if err != nil {
+			log.Printf("❌ Error loading saved queries: %v", err)
+			os.Exit(1)
+		}
+
+		if machineOutput() {
+			records := []internal.SavedQueryRecord{}
+			for _, saved := range queries {
+				records = append(records, internal.SavedQueryRecord{Name: saved.Name, Query: saved.Query, NoteID: saved.NoteID})
+			}
+			printRecords(records)
+			return
+		}
+
+		if len(queries) == 0 {
+			log.Println("⚠️ No saved queries.")
+			return
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleDouble)
+		t.Style().Options.SeparateRows = false
+		t.AppendHeader(table.Row{"Name", "Query", "Index note"})
+		for _, saved := range queries {
+			t.AppendRow(table.Row{saved.Name, saved.Query, saved.NoteID})
+		}
+		t.Render()
+	},
+}
+
+var queryDeleteCmd = &cobra.Command{
+	Use:     "delete [name]",
+	Short:   "Delete a saved query",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"rm"},
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return
+		}
+
+		queries, err := internal.LoadSavedQueries(*config)
+		if err != nil {
+			log.Printf("❌ Error loading saved queries: %v", err)
+			return
+		}
+
+		i := internal.FindSavedQuery(queries, name)
+		if i < 0 {
+			log.Printf("⚠️ Query %q not found", name)
+			return
+		}
+		queries = append(queries[:i], queries[i+1:]...)
+
+		if err := internal.SaveSavedQueries(queries, *config); err != nil {
+			log.Printf("❌ Error saving queries: %v", err)
+			return
+		}
+
+		log.Printf("✅ Query %q deleted", name)
+	},
+}
+
+var queryMaterializeCmd = &cobra.Command{
+	Use:   "materialize [name]",
+	Short: "Create an index note listing the matches of a saved query",
+	Long: `Create an index note whose marker-delimited section lists the notes matching a saved query.
+
+The section is regenerated by "zk query run", "zk query refresh" and "zk sync";
+text outside the markers is left untouched.`,
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"mat"},
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return
+		}
+
+		queries, err := internal.LoadSavedQueries(*config)
+		if err != nil {
+			log.Printf("❌ Error loading saved queries: %v", err)
+			return
+		}
+
+		i := internal.FindSavedQuery(queries, name)
+		if i < 0 {
+			log.Printf("⚠️ Query %q not found", name)
+			return
+		}
+
+		if queries[i].NoteID == "" {
+			title := queryNoteTitle
+			if title == "" {
+				title = fmt.Sprintf("Query: %s", name)
+			}
+
+			_, note, err := createNewNote(title, "index", []string{}, "", *config)
+			if err != nil {
+				log.Printf("❌ Failed to create index note: %v", err)
+				return
+			}
+
+			queries[i].NoteID = note.NoteID
+			if err := internal.SaveSavedQueries(queries, *config); err != nil {
+				log.Printf("❌ Error saving queries: %v", err)
+				return
+			}
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			return
+		}
+
+		count, err := refreshMaterializedQuery(queries[i], zettels, *config)
+		if err != nil {
+			log.Printf("%v", err)
+			return
+		}
+
+		if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+			log.Printf("❌ Error updating JSON: %v", err)
+			return
+		}
+
+		log.Printf("✅ Query %q materialised into note %s (%d notes)", name, queries[i].NoteID, count)
+	},
+}
+
+var queryRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Regenerate the index notes of all materialised queries",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return
+		}
+
+		if err := refreshMaterializedQueries(*config); err != nil {
+			log.Printf("❌ Error refreshing queries: %v", err)
+		}
+	},
+}
+
+func init() {
+	queryCmd.AddCommand(querySaveCmd)
+	queryCmd.AddCommand(queryRunCmd)
+	queryCmd.AddCommand(queryListCmd)
+	queryCmd.AddCommand(queryDeleteCmd)
+	queryCmd.AddCommand(queryMaterializeCmd)
+	queryCmd.AddCommand(queryRefreshCmd)
+	rootCmd.AddCommand(queryCmd)
+
+	queryMaterializeCmd.Flags().StringVar(&queryNoteTitle, "title", "", "Title of the index note")
+}
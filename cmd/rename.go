@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var renameYes bool
+var renameDryRun bool
+
+// Note rewritten by a rename
+type renamedNote struct {
+	index   int    // Index of the note in zettels
+	body    string // Rewritten body, without the front matter
+	changes []internal.LineChange
+}
+
+// Rewrite the links to the renamed note in every other active note
+func planRenameLinks(zettels []internal.Zettel, target internal.Zettel, newTitle string) ([]renamedNote, error) {
+	var planned []renamedNote
+	for i, z := range zettels {
+		if z.NoteID == target.NoteID || z.Deleted {
+			continue
+		}
+		content, err := os.ReadFile(z.NotePath)
+		if err != nil {
+			continue
+		}
+		updated, changes := internal.RetitleLinks(string(content), target, target.Title, newTitle)
+		if len(changes) == 0 {
+			continue
+		}
+		_, body, err := internal.ParseFrontMatter(updated)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to parse front matter of %s: %w", z.NotePath, err)
+		}
+		planned = append(planned, renamedNote{index: i, body: body, changes: changes})
+	}
+	return planned, nil
+}
+
+// Print the changes a rename makes
+func printRenamePreview(target internal.Zettel, newTitle string, heading bool, planned []renamedNote, zettels []internal.Zettel) {
+	noteStyle := color.New(color.FgHiCyan, color.Bold).SprintFunc()
+	removed := color.New(color.FgRed).SprintFunc()
+	added := color.New(color.FgGreen).SprintFunc()
+
+	fmt.Printf("%s\n", noteStyle(fmt.Sprintf("[%s] %s", target.ID, target.NotePath)))
+	fmt.Printf("  %s\n  %s\n", removed("- title: "+target.Title), added("+ title: "+newTitle))
+	if heading {
+		fmt.Printf("  %s\n  %s\n", removed("- ## "+target.Title), added("+ ## "+newTitle))
+	}
+
+	for _, note := range planned {
+		z := zettels[note.index]
+		fmt.Printf("%s\n", noteStyle(fmt.Sprintf("[%s] %s", z.ID, z.NotePath)))
+		printLineChanges(note.changes)
+	}
+}
+
+// Print changed lines as a diff with their line numbers
+func printLineChanges(changes []internal.LineChange) {
+	lineStyle := color.New(color.FgHiBlack).SprintFunc()
+	removed := color.New(color.FgRed).SprintFunc()
+	added := color.New(color.FgGreen).SprintFunc()
+
+	for _, change := range changes {
+		fmt.Printf("  %s %s\n", lineStyle(fmt.Sprintf("%d:", change.Line)), removed("- "+strings.TrimSpace(change.Before)))
+		fmt.Printf("  %s %s\n", lineStyle(strings.Repeat(" ", len(fmt.Sprint(change.Line))+1)), added("+ "+strings.TrimSpace(change.After)))
+	}
+}
+
+// Ask whether to apply previewed changes
+func confirmChanges() (bool, error) {
+	confirmed := false
+	prompt := &survey.Confirm{Message: "Apply these changes?"}
+	if err := survey.AskOne(prompt, &confirmed); err != nil {
+		return false, fmt.Errorf("❌ Failed to get user input: %w", err)
+	}
+	return confirmed, nil
+}
+
+// Rewrite a note's front matter and body, and copy the changed fields to its index entry
+func writeNoteBody(zettel *internal.Zettel, update func(frontMatter *internal.FrontMatter, body string) string) error {
+	content, err := os.ReadFile(zettel.NotePath)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read note: %w", err)
+	}
+	frontMatter, body, err := internal.ParseFrontMatter(string(content))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse front matter: %w", err)
+	}
+	body = update(&frontMatter, body)
+	frontMatter.UpdatedAt = time.Now().Format(internal.TimestampLayout)
+
+	if err := os.WriteFile(zettel.NotePath, []byte(internal.UpdateFrontMatter(&frontMatter, body)), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write note: %w", err)
+	}
+	zettel.Title = frontMatter.Title
+	zettel.Tags = frontMatter.Tags
+	zettel.Aliases = frontMatter.Aliases
+	zettel.Links = frontMatter.Links
+	zettel.UpdatedAt = frontMatter.UpdatedAt
+	return nil
+}
+
+// Retitle a note and rewrite the planned links to it, in the note files and
+// in zettels (saving the index is left to the caller)
+func applyRename(zettels []internal.Zettel, i int, newTitle string, planned []renamedNote) error {
+	oldTitle := zettels[i].Title
+	if err := writeNoteBody(&zettels[i], func(frontMatter *internal.FrontMatter, body string) string {
+		frontMatter.Title = newTitle
+		body, _ = internal.RetitleHeading(body, oldTitle, newTitle)
+		return body
+	}); err != nil {
+		return err
+	}
+
+	for _, note := range planned {
+		if err := writeNoteBody(&zettels[note.index], func(_ *internal.FrontMatter, _ string) string {
+			return note.body
+		}); err != nil {
+			log.Printf("%v", err)
+		}
+	}
+	return nil
+}
+
+var renameCmd = &cobra.Command{
+	Use:   "rename [id] [new title]",
+	Short: "Retitle a note and the links pointing to it",
+	Long: `Change the title of a note in its front matter, its leading "## Title"
+heading and the index, and rewrite the text of the links pointing to it
+across the vault ([Old Title](NoteID.md), [[Old Title]]). Links whose text
+differs from the old title are left as written.
+
+The changed files are previewed before anything is written.`,
+	Args:    cobra.ExactArgs(2),
+	Aliases: []string{"rn"},
+	Run: func(cmd *cobra.Command, args []string) {
+		newTitle := strings.TrimSpace(args[1])
+		if newTitle == "" {
+			log.Println("❌ The new title must not be empty")
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		i, err := internal.ResolveNote(zettels, args[0])
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		target := zettels[i]
+		if target.Title == newTitle {
+			log.Printf("⚠️ [%s] is already titled %s", target.ID, newTitle)
+			return
+		}
+
+		content, err := os.ReadFile(target.NotePath)
+		if err != nil {
+			log.Printf("❌ Failed to read note: %v", err)
+			os.Exit(1)
+		}
+		_, body, err := internal.ParseFrontMatter(string(content))
+		if err != nil {
+			log.Printf("❌ Failed to parse front matter: %v", err)
+			os.Exit(1)
+		}
+		_, heading := internal.RetitleHeading(body, target.Title, newTitle)
+
+		planned, err := planRenameLinks(zettels, target, newTitle)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
+		printRenamePreview(target, newTitle, heading, planned, zettels)
+		if renameDryRun {
+			return
+		}
+		if !renameYes {
+			if !internal.IsInteractive() {
+				log.Println("❌ Confirming the rename requires a terminal; use --yes to apply it")
+				os.Exit(1)
+			}
+			confirmed, err := confirmChanges()
+			if err != nil {
+				log.Printf("%v", err)
+				os.Exit(1)
+			}
+			if !confirmed {
+				log.Println("⚠️ Rename cancelled")
+				return
+			}
+		}
+
+		if err := applyRename(zettels, i, newTitle, planned); err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
+		if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+			log.Printf("❌ Error updating JSON: %v", err)
+			os.Exit(1)
+		}
+
+		log.Printf("✅ Renamed [%s] %s to %s (%d linking notes updated)", target.ID, target.Title, newTitle, len(planned))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+	renameCmd.Flags().BoolVarP(&renameYes, "yes", "y", false, "Apply the rename without asking")
+	renameCmd.Flags().BoolVar(&renameDryRun, "dry-run", false, "Only preview the changes")
+}
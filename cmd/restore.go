@@ -24,8 +24,6 @@ var restoreCmd = &cobra.Command{
 	Aliases: []string{"rs"},
 	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		restoreId := args[0]
-
 		config, err := internal.LoadConfig()
 		if err != nil {
 			log.Printf("❌ Error loading config: %v", err)
@@ -47,6 +45,12 @@ var restoreCmd = &cobra.Command{
 			return
 		}
 
+		restoreId, err := resolveRestorableNoteID(zettels, args[0])
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
 		// Search for the note
 		found := false
 		for i := range zettels {
@@ -6,9 +6,12 @@ package cmd
 import (
 	"os"
 
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
 	"github.com/spf13/cobra"
 )
 
+var outputFormat string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "zk",
@@ -38,6 +41,9 @@ Examples:
   # Change task status
   zk task status 123 Done
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return internal.ValidateOutputFormat(outputFormat)
+	},
 }
 
 func Execute() {
@@ -48,5 +54,6 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", internal.OutputTable, "Output format of read commands (table, json, yaml, csv, tsv, ids)")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
@@ -6,6 +6,10 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/nakachan-ing/Zettelkasten-cli/internal"
@@ -15,13 +19,212 @@ import (
 var searchTitle bool
 var searchTypes []string
 var searchTags []string
+var searchTagMode string
 var searchContext int
 var interactive bool
+var searchBackend string
+var searchLimit int
+var searchSnippetWidth int
+
+// Matching lines shown per note by the built-in backend
+const searchMaxLines = 3
+
+// Search backends
+const (
+	backendBuiltin = "builtin"
+	backendRipgrep = "ripgrep"
+)
+
+// Search notes with ripgrep (optional backend)
+func runRipgrepSearch(keyword string, config *internal.Config) (map[string][]internal.MatchLine, error) {
+	var rgArgs []string
+	rgArgs = append(rgArgs, "--json", "--ignore-case")
+	rgArgs = append(rgArgs, "-C", fmt.Sprintf("%d", searchContext)) // Context lines
+
+	// If only --interactive is used, search all notes
+	if interactive && keyword == "" && !searchTitle && len(searchTags) == 0 && len(searchTypes) == 0 {
+		rgArgs = append(rgArgs, "-e", ".*") // Search everything
+	}
+
+	// Search by title (`--title`)
+	if searchTitle {
+		if keyword != "" {
+			rgArgs = append(rgArgs, "-e", fmt.Sprintf("^title:\\s*.*%s", keyword))
+		} else {
+			rgArgs = append(rgArgs, "-e", "^title:\\s*") // Search all titles
+		}
+	}
+
+	// Search by note type (`--type`)
+	if len(searchTypes) > 0 {
+		for _, t := range searchTypes {
+			rgArgs = append(rgArgs, "-e", fmt.Sprintf(`^type:\s*%s`, t))
+		}
+	}
+
+	// Search by tag (`--tag`)
+	if len(searchTags) > 0 {
+		rgArgs = append(rgArgs, "--multiline", "--multiline-dotall")
+		for _, tag := range searchTags {
+			rgArgs = append(rgArgs, "-e", fmt.Sprintf(`^tags:.*%s`, regexp.QuoteMeta(internal.TagFilterTerm(tag))))
+		}
+	}
+
+	// Full-text search
+	if keyword != "" && !searchTitle && len(searchTags) == 0 && len(searchTypes) == 0 {
+		rgArgs = append(rgArgs, "-e", keyword)
+	}
+
+	// Append search directory
+	rgArgs = append(rgArgs, config.NoteDir)
+
+	var out bytes.Buffer
+	rgCmd := exec.Command("rg", rgArgs...)
+	rgCmd.Stdout = &out
+	rgCmd.Stderr = &out
+
+	err := rgCmd.Run()
+	output := out.String()
+
+	if err != nil && output == "" {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	// Parse search results
+	return internal.ParseRipgrepOutput(output)
+}
+
+// Check whether a note passes the `--type` and `--tag` filters
+func matchSearchFilters(zettel internal.Zettel) bool {
+	if len(searchTypes) > 0 {
+		match := false
+		for _, t := range searchTypes {
+			if strings.EqualFold(zettel.NoteType, t) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	return internal.MatchTags(zettel.Tags, searchTags, searchTagMode)
+}
+
+// Search notes with the built-in index
+func runBuiltinSearch(keyword string, config *internal.Config) ([]internal.SearchHit, map[string]internal.Zettel, error) {
+	zettels, err := internal.LoadJson(*config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query, err := internal.ParseQuery(keyword)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	// Notes in the trash or the archive are only searched when the query asks for them
+	active := internal.QueryScope(zettels, query, false, true)
+	byNoteID := make(map[string]internal.Zettel)
+	for _, zettel := range active {
+		byNoteID[zettel.NoteID] = zettel
+	}
+	queryContext := internal.NewQueryContext(*config, time.Now())
+
+	// Free-text words and phrases are ranked; field terms only filter
+	text := strings.Join(internal.QueryText(query), " ")
+
+	var hits []internal.SearchHit
+	if text == "" {
+		// No keyword: list every note passing the filters, most recently updated first
+		sort.SliceStable(active, func(i, j int) bool {
+			return active[i].UpdatedAt > active[j].UpdatedAt
+		})
+		for _, zettel := range active {
+			hits = append(hits, internal.SearchHit{NoteID: zettel.NoteID})
+		}
+	} else {
+		index, err := internal.BuildSearchIndex(active, *config)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var fields []string
+		if searchTitle {
+			fields = []string{"title"}
+		}
+		hits = index.Search(text, fields)
+	}
+
+	filtered := []internal.SearchHit{}
+	for _, hit := range hits {
+		zettel := byNoteID[hit.NoteID]
+		if query != nil && !query.Eval(queryContext, zettel) {
+			continue
+		}
+		if matchSearchFilters(zettel) {
+			filtered = append(filtered, hit)
+		}
+	}
+	if searchLimit > 0 && len(filtered) > searchLimit {
+		filtered = filtered[:searchLimit]
+	}
+	return filtered, byNoteID, nil
+}
+
+// Group ripgrep results per note, ranked by the number of matching lines
+func ripgrepNoteResults(matches map[string][]internal.MatchLine, config *internal.Config) ([]internal.NoteResult, error) {
+	zettels, err := internal.LoadJson(*config)
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]internal.Zettel)
+	for _, zettel := range zettels {
+		byPath[filepath.Clean(zettel.NotePath)] = zettel
+	}
+
+	results := []internal.NoteResult{}
+	for path, lines := range matches {
+		zettel, ok := byPath[filepath.Clean(path)]
+		if !ok {
+			// Files missing from `zettel.json` are still shown by name
+			zettel = internal.Zettel{Title: filepath.Base(path), NotePath: path}
+		} else if !matchSearchFilters(zettel) {
+			// ripgrep patterns only preselect notes by their front matter
+			continue
+		}
+		sort.SliceStable(lines, func(i, j int) bool { return lines[i].Line < lines[j].Line })
+		results = append(results, internal.NoteResult{Zettel: zettel, Lines: lines})
+	}
+	internal.SortNoteResults(results)
+	return results, nil
+}
+
+// Group built-in search hits per note with their matching lines
+func builtinNoteResults(hits []internal.SearchHit, byNoteID map[string]internal.Zettel) []internal.NoteResult {
+	results := []internal.NoteResult{}
+	for _, hit := range hits {
+		zettel := byNoteID[hit.NoteID]
+		results = append(results, internal.NoteResult{
+			Zettel: zettel,
+			Score:  hit.Score,
+			Lines:  internal.LoadMatchLines(zettel.NotePath, hit.Terms, searchMaxLines),
+		})
+	}
+	return results
+}
 
 // searchCmd represents the search command
 var searchCmd = &cobra.Command{
-	Use:     "search [query]",
-	Short:   "Search notes",
+	Use:   "search [query]",
+	Short: "Search notes",
+	Long: `Search notes with a ranked full-text search.
+
+The query accepts the same syntax as ` + "`zk list`" + `, e.g.:
+  zk search 'kubernetes tag:devops -type:fleeting "rolling update"'
+
+Free-text words and phrases are ranked with BM25; field terms filter the results.`,
 	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"f"},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -46,9 +249,10 @@ var searchCmd = &cobra.Command{
 			log.Printf("⚠️ Trash cleanup failed: %v", err)
 		}
 
-		var rgArgs []string
-		rgArgs = append(rgArgs, "--json", "--ignore-case", "--only-matching")
-		rgArgs = append(rgArgs, "-C", fmt.Sprintf("%d", searchContext)) // Context lines
+		if err := internal.ValidateTagMode(searchTagMode); err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
 
 		// Validate that at least one search criteria is provided
 		if keyword == "" && !searchTitle && len(searchTags) == 0 && len(searchTypes) == 0 && !interactive {
@@ -56,62 +260,58 @@ var searchCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// If only --interactive is used, search all notes
-		if interactive && keyword == "" && !searchTitle && len(searchTags) == 0 && len(searchTypes) == 0 {
-			rgArgs = append(rgArgs, "-e", ".*") // Search everything
+		backend := searchBackend
+		if backend == "" {
+			backend = config.Search.Backend
 		}
-
-		// Search by title (`--title`)
-		if searchTitle {
-			if keyword != "" {
-				rgArgs = append(rgArgs, "-e", fmt.Sprintf("^title:\\s*.*%s", keyword))
-			} else {
-				rgArgs = append(rgArgs, "-e", "^title:\\s*") // Search all titles
-			}
+		if backend == "" {
+			backend = backendBuiltin
 		}
 
-		// Search by note type (`--type`)
-		if len(searchTypes) > 0 {
-			for _, t := range searchTypes {
-				rgArgs = append(rgArgs, "-e", fmt.Sprintf(`^type:\s*%s`, t))
+		var results []internal.NoteResult
+		switch backend {
+		case backendRipgrep, "rg":
+			// ripgrep takes the keyword as a pattern and knows nothing of the query language
+			if query, err := internal.ParseQuery(keyword); err == nil && internal.IsStructuredQuery(query) {
+				log.Printf("❌ The ripgrep backend does not support query fields, OR or NOT; use --backend builtin")
+				os.Exit(1)
 			}
-		}
-
-		// Search by tag (`--tag`)
-		if len(searchTags) > 0 {
-			rgArgs = append(rgArgs, "--multiline", "--multiline-dotall")
-			for _, tag := range searchTags {
-				rgArgs = append(rgArgs, "-e", fmt.Sprintf(`^tags:.*%s`, tag))
+			matches, err := runRipgrepSearch(keyword, config)
+			if err != nil && len(matches) == 0 {
+				log.Printf("❌ Search failed: %v", err)
+				os.Exit(1)
 			}
-		}
 
-		// Full-text search
-		if keyword != "" && !searchTitle && len(searchTags) == 0 && len(searchTypes) == 0 {
-			rgArgs = append(rgArgs, "-e", keyword)
-		}
-
-		// Append search directory
-		rgArgs = append(rgArgs, config.NoteDir)
-
-		var out bytes.Buffer
-		rgCmd := exec.Command("rg", rgArgs...)
-		rgCmd.Stdout = &out
-		rgCmd.Stderr = &out
+			results, err = ripgrepNoteResults(matches, config)
+			if err != nil {
+				log.Printf("❌ Error loading JSON: %v", err)
+				os.Exit(1)
+			}
 
-		err = rgCmd.Run()
-		output := out.String()
+		case backendBuiltin:
+			hits, byNoteID, err := runBuiltinSearch(keyword, config)
+			if err != nil {
+				log.Printf("❌ Search failed: %v", err)
+				os.Exit(1)
+			}
+			results = builtinNoteResults(hits, byNoteID)
 
-		if err != nil && output == "" {
-			log.Printf("❌ Search failed: %v", err)
+		default:
+			log.Printf("❌ Invalid search backend %q: must be '%s' or '%s'", backend, backendBuiltin, backendRipgrep)
 			os.Exit(1)
 		}
 
-		// Parse search results
-		results, err := internal.ParseRipgrepOutput(output)
-
-		if err != nil {
-			log.Printf("❌ Failed to parse ripgrep output: %v", err)
-			os.Exit(1)
+		if machineOutput() && !interactive {
+			records := []internal.SearchRecord{}
+			for _, result := range results {
+				records = append(records, internal.SearchRecord{
+					NoteRecord: internal.NewNoteRecord(result.Zettel),
+					Score:      result.Score,
+					Matches:    result.MatchTexts(),
+				})
+			}
+			printRecords(records)
+			return
 		}
 
 		if len(results) == 0 {
@@ -119,12 +319,45 @@ var searchCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Interactive mode
+		// Interactive mode: pick a result and show it
 		if interactive {
-			internal.InteractiveSearch(results)
-		} else {
-			internal.DisplayResults(results)
+			items := []internal.PickerItem{}
+			for _, result := range results {
+				item := internal.PickerItem{Zettel: result.Zettel}
+				if texts := result.MatchTexts(); len(texts) > 0 {
+					item.Detail = texts[0]
+				}
+				items = append(items, item)
+			}
+
+			picked, err := internal.PickNote(items, "Search")
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			if picked == nil {
+				return
+			}
+			zettels, err := internal.LoadJson(*config)
+			if err != nil {
+				log.Printf("❌ Error loading JSON: %v", err)
+				os.Exit(1)
+			}
+			if err := showNote(*picked, zettels); err != nil {
+				log.Printf("%v", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		width := searchSnippetWidth
+		if width == 0 {
+			width = config.Search.SnippetWidth
+		}
+		if width == 0 {
+			width = internal.DefaultSnippetWidth
 		}
+		internal.DisplaySearchResults(results, width)
 	},
 }
 
@@ -133,7 +366,11 @@ func init() {
 
 	searchCmd.Flags().BoolVar(&searchTitle, "title", false, "Search by title")
 	searchCmd.Flags().StringSliceVar(&searchTypes, "type", []string{}, "Filter by note type")
-	searchCmd.Flags().StringSliceVar(&searchTags, "tag", []string{}, "Filter by tags")
+	searchCmd.Flags().StringSliceVar(&searchTags, "tag", []string{}, "Filter by tags (also matching sub-tags; '=tag' for the tag alone)")
+	searchCmd.Flags().StringVar(&searchTagMode, "tag-mode", internal.TagModeAny, "Match any or all of the --tag values (any, all)")
 	searchCmd.Flags().IntVar(&searchContext, "context", 0, "Show N lines before and after the search result")
-	searchCmd.Flags().BoolVar(&interactive, "interactive", false, "Use interactive search with fzf")
+	searchCmd.Flags().BoolVar(&interactive, "interactive", false, "Pick a result with the interactive finder and show it")
+	searchCmd.Flags().StringVar(&searchBackend, "backend", "", "Search backend (builtin, ripgrep)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 0, "Maximum number of results (0 for all)")
+	searchCmd.Flags().IntVar(&searchSnippetWidth, "snippet-width", 0, "Number of characters shown around a match (-1 for whole lines)")
 }
@@ -14,15 +14,92 @@ import (
 )
 
 var meta bool
+var showGraph bool
+
+// Print the metadata and rendered content of a note
+func showNote(zettel internal.Zettel, zettels []internal.Zettel) error {
+	note, err := os.ReadFile(zettel.NotePath)
+	if err != nil {
+		return fmt.Errorf("❌ Error reading note file (%s): %w", zettel.NotePath, err)
+	}
+
+	frontMatter, body, err := internal.ParseFrontMatter(string(note))
+	if err != nil {
+		return fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	if machineOutput() {
+		done, total := internal.CheckboxProgress(internal.ParseCheckboxes(string(note)))
+		record := internal.ShowRecord{
+			NoteRecord:     internal.NewNoteRecord(zettel),
+			ChecklistDone:  done,
+			ChecklistTotal: total,
+		}
+		if !meta {
+			record.Body = body
+		}
+		printRecord(record)
+		return nil
+	}
+
+	titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
+	frontMatterStyle := color.New(color.FgHiGreen).SprintFunc()
+
+	fmt.Printf("[%v] %v\n", titleStyle(frontMatter.ID), titleStyle(frontMatter.Title))
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("Type: %v\n", frontMatterStyle(frontMatter.Type))
+	fmt.Printf("Tags: %v\n", frontMatterStyle(frontMatter.Tags))
+	fmt.Printf("Links: %v\n", frontMatterStyle(frontMatter.Links))
+	if backlinks := internal.Backlinks(zettel, zettels); len(backlinks) > 0 {
+		labels := []string{}
+		for _, backlink := range backlinks {
+			label := fmt.Sprintf("[%s] %s", backlink.Source.ID, backlink.Source.Title)
+			if backlink.Link.Rel != "" {
+				label += fmt.Sprintf(" (%s)", backlink.Link.Rel)
+			}
+			labels = append(labels, label)
+		}
+		fmt.Printf("Backlinks: %v\n", frontMatterStyle(strings.Join(labels, ", ")))
+	}
+	fmt.Printf("Task status: %v\n", frontMatterStyle(frontMatter.TaskStatus))
+	if progress := internal.FormatProgress(internal.CheckboxProgress(internal.ParseCheckboxes(string(note)))); progress != "" {
+		fmt.Printf("Checklist: %v\n", frontMatterStyle(progress))
+	}
+	fmt.Printf("Created at: %v\n", frontMatterStyle(frontMatter.CreatedAt))
+	fmt.Printf("Updated at: %v\n", frontMatterStyle(frontMatter.UpdatedAt))
+
+	// Render Markdown content unless --meta flag is used
+	if !meta {
+		renderedContent, err := glamour.Render(body, "dark")
+		if err != nil {
+			log.Printf("⚠️ Failed to render markdown content: %v", err)
+		} else {
+			fmt.Println(renderedContent)
+		}
+	}
+	return nil
+}
 
 // showCmd represents the show command
 var showCmd = &cobra.Command{
-	Use:     "show [id]",
-	Short:   "Show a note",
-	Args:    cobra.ExactArgs(1),
+	Use:   "show [id]",
+	Short: "Show a note",
+	Long: `Show a note.
+
+With --graph, the link tree around the note is shown instead of its content
+(see zk neighbors).
+
+Without an ID, pick the note from an interactive fuzzy finder.`,
+	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"s"},
 	Run: func(cmd *cobra.Command, args []string) {
-		noteId := args[0]
+		if showGraph {
+			if err := validateNeighbourFlags(); err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			meta = true
+		}
 
 		config, err := internal.LoadConfig()
 		if err != nil {
@@ -45,57 +122,59 @@ var showCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Find and display the requested note
-		found := false
-		for _, zettel := range zettels {
-			if noteId == zettel.ID {
-				found = true
-
-				note, err := os.ReadFile(zettel.NotePath)
-				if err != nil {
-					log.Printf("❌ Error reading note file (%s): %v", zettel.NotePath, err)
-					os.Exit(1)
-				}
-
-				titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
-				frontMatterStyle := color.New(color.FgHiGreen).SprintFunc()
-
-				frontMatter, body, err := internal.ParseFrontMatter(string(note))
-				if err != nil {
-					log.Printf("❌ Error parsing front matter: %v", err)
-					os.Exit(1)
-				}
-
-				fmt.Printf("[%v] %v\n", titleStyle(frontMatter.ID), titleStyle(frontMatter.Title))
-				fmt.Println(strings.Repeat("-", 50))
-				fmt.Printf("Type: %v\n", frontMatterStyle(frontMatter.Type))
-				fmt.Printf("Tags: %v\n", frontMatterStyle(frontMatter.Tags))
-				fmt.Printf("Links: %v\n", frontMatterStyle(frontMatter.Links))
-				fmt.Printf("Task status: %v\n", frontMatterStyle(frontMatter.TaskStatus))
-				fmt.Printf("Created at: %v\n", frontMatterStyle(frontMatter.CreatedAt))
-				fmt.Printf("Updated at: %v\n", frontMatterStyle(frontMatter.UpdatedAt))
-
-				// Render Markdown content unless --meta flag is used
-				if !meta {
-					renderedContent, err := glamour.Render(body, "dark")
-					if err != nil {
-						log.Printf("⚠️ Failed to render markdown content: %v", err)
-					} else {
-						fmt.Println(renderedContent)
-					}
-				}
-				break
+		var target internal.Zettel
+		if len(args) == 0 {
+			picked, err := pickActiveNote(zettels, "Show")
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			if picked == nil {
+				return
+			}
+			target = *picked
+		} else {
+			// Find the requested note
+			i, err := internal.ResolveNote(zettels, args[0])
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			target = zettels[i]
+		}
+
+		// The link tree replaces the note record, its root being the note itself
+		if showGraph && machineOutput() {
+			graph := activeGraph(zettels)
+			if !graph.HasNode(target.NoteID) {
+				log.Printf("❌ Note [%s] %s is archived or deleted", target.ID, target.Title)
+				os.Exit(1)
 			}
+			printRecords(internal.NewNeighbourRecords(internal.NeighbourTree(graph, target.NoteID, neighborsDepth, strings.ToLower(neighborsDirection))))
+			return
 		}
 
-		if !found {
-			log.Printf("❌ Note with ID %s not found", noteId)
+		if err := showNote(target, zettels); err != nil {
+			log.Printf("%v", err)
 			os.Exit(1)
 		}
+
+		if showGraph {
+			graph := activeGraph(zettels)
+			if !graph.HasNode(target.NoteID) {
+				log.Printf("⚠️ Note [%s] %s is archived or deleted", target.ID, target.Title)
+				return
+			}
+			fmt.Println()
+			printNeighbourTree(graph, target.NoteID, neighborsDepth, strings.ToLower(neighborsDirection))
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(showCmd)
 	showCmd.Flags().BoolVar(&meta, "meta", false, "Show only metadata without note content")
+	showCmd.Flags().BoolVar(&showGraph, "graph", false, "Show the link tree around the note instead of its content")
+	showCmd.Flags().IntVar(&neighborsDepth, "depth", 2, "With --graph, number of link levels to follow")
+	showCmd.Flags().StringVar(&neighborsDirection, "direction", internal.DirectionBoth, "With --graph, links to follow: 'both', 'out' (links) or 'in' (backlinks)")
 }
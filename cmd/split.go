@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var splitHeadings []string
+var splitAll bool
+var splitType string
+var splitYes bool
+var splitDryRun bool
+
+// Option shown for a section, indented by heading level
+func sectionOption(section internal.NoteSection) string {
+	return fmt.Sprintf("%s%s %s", strings.Repeat("  ", section.Level-2), strings.Repeat("#", section.Level), section.Title)
+}
+
+// Let the user choose which sections to split off
+func selectSections(sections []internal.NoteSection) ([]internal.NoteSection, error) {
+	options := []string{}
+	for _, section := range sections {
+		options = append(options, sectionOption(section))
+	}
+
+	// Selected by position: sections may share a heading
+	var selected []int
+	prompt := &survey.MultiSelect{
+		Message: "Select sections to split into new notes:",
+		Options: options,
+	}
+	if err := survey.AskOne(prompt, &selected, nil); err != nil {
+		return nil, fmt.Errorf("❌ Failed to get user input: %w", err)
+	}
+
+	chosen := []internal.NoteSection{}
+	for _, n := range selected {
+		chosen = append(chosen, sections[n])
+	}
+	return chosen, nil
+}
+
+// Sections chosen with --heading, --all or interactively, in document order.
+// Sections inside another chosen section move along with it.
+func chooseSections(sections []internal.NoteSection) ([]internal.NoteSection, error) {
+	var chosen []internal.NoteSection
+	switch {
+	case splitAll:
+		chosen = sections
+	case len(splitHeadings) > 0:
+		for _, heading := range splitHeadings {
+			found := false
+			for _, section := range sections {
+				if strings.EqualFold(section.Title, strings.TrimSpace(heading)) {
+					chosen = append(chosen, section)
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("❌ No section titled %q", heading)
+			}
+		}
+	default:
+		if !internal.IsInteractive() {
+			return nil, fmt.Errorf("❌ Choosing sections requires a terminal; use --heading or --all")
+		}
+		var err error
+		if chosen, err = selectSections(sections); err != nil {
+			return nil, err
+		}
+	}
+
+	var outer []internal.NoteSection
+	for _, section := range sections {
+		selected, nested := false, false
+		for _, c := range chosen {
+			if c.Start == section.Start {
+				selected = true
+			}
+			if section.Within(c) {
+				nested = true
+			}
+		}
+		if selected && !nested {
+			outer = append(outer, section)
+		}
+	}
+	return outer, nil
+}
+
+// A note whose links to sections about to be split off will follow them
+type plannedSectionLinks struct {
+	index   int
+	noteID  string
+	changes []internal.LineChange
+	targets []string // Titles of the new notes each changed line points at
+}
+
+// Find the links to the chosen sections across the vault
+func planSectionLinks(zettels []internal.Zettel, original internal.Zettel, chosen []internal.NoteSection) []plannedSectionLinks {
+	var planned []plannedSectionLinks
+	for i, z := range zettels {
+		if z.Deleted || z.NoteID == original.NoteID {
+			continue
+		}
+		content, err := os.ReadFile(z.NotePath)
+		if err != nil {
+			continue
+		}
+		note := plannedSectionLinks{index: i, noteID: z.NoteID}
+		updated := string(content)
+		byLine := make(map[int]int)
+		for _, section := range chosen {
+			// The new note has no ID yet; its title stands in for it
+			var changes []internal.LineChange
+			updated, changes = internal.RedirectSectionLinks(updated, original, section.Title, internal.Zettel{Title: section.Title})
+			for _, change := range changes {
+				if n, ok := byLine[change.Line]; ok {
+					note.targets[n] += ", " + section.Title
+					continue
+				}
+				byLine[change.Line] = len(note.changes)
+				note.changes = append(note.changes, change)
+				note.targets = append(note.targets, section.Title)
+			}
+		}
+		if len(note.changes) > 0 {
+			planned = append(planned, note)
+		}
+	}
+	return planned
+}
+
+// Remove the notes created by an interrupted split, files and index entries
+func discardNotes(notes []internal.Zettel, config *internal.Config) {
+	if len(notes) == 0 {
+		return
+	}
+	discarded := make(map[string]bool)
+	for _, note := range notes {
+		discarded[note.NoteID] = true
+		if err := os.Remove(note.NotePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("⚠️ Failed to remove %s: %v", note.NotePath, err)
+		}
+	}
+
+	zettels, err := internal.LoadJson(*config)
+	if err != nil {
+		log.Printf("⚠️ Failed to remove the new notes from the index: %v", err)
+		return
+	}
+	kept := []internal.Zettel{}
+	for _, z := range zettels {
+		if !discarded[z.NoteID] {
+			kept = append(kept, z)
+		}
+	}
+	if err := internal.SaveUpdatedJson(kept, config); err != nil {
+		log.Printf("⚠️ Failed to remove the new notes from the index: %v", err)
+		return
+	}
+	log.Printf("⚠️ Removed the %d notes created by the split", len(notes))
+}
+
+// Create a note for each chosen section and replace the sections in the
+// original. Until the original is written, a failure removes the notes
+// created so far again. Returns the reloaded index and the new notes.
+func splitNote(original internal.Zettel, body string, chosen []internal.NoteSection, branches []string, noteType string, config *internal.Config) ([]internal.Zettel, []internal.Zettel, error) {
+	var created []internal.Zettel
+	fail := func(err error) ([]internal.Zettel, []internal.Zettel, error) {
+		discardNotes(created, config)
+		return nil, nil, err
+	}
+
+	// Note IDs have a resolution of one second
+	for n, section := range chosen {
+		if n > 0 {
+			time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+		}
+		_, zettel, err := createNewNote(section.Title, noteType, append([]string{}, original.Tags...), branches[n], *config)
+		if err != nil {
+			return fail(fmt.Errorf("❌ Failed to create note: %w", err))
+		}
+		created = append(created, zettel)
+	}
+
+	// Reload to pick up the short IDs given to the new notes
+	zettels, err := internal.LoadJson(*config)
+	if err != nil {
+		return fail(fmt.Errorf("❌ Error loading JSON: %w", err))
+	}
+	indexOf := make(map[string]int)
+	for i, z := range zettels {
+		indexOf[z.NoteID] = i
+	}
+	oi, ok := indexOf[original.NoteID]
+	if !ok {
+		return fail(fmt.Errorf("❌ Note [%s] %s is no longer in the index", original.ID, original.Title))
+	}
+
+	backlink := fmt.Sprintf("Split from [%s](%s.md)", original.Title, original.NoteID)
+	replacements := []string{}
+	for n, section := range chosen {
+		ni, ok := indexOf[created[n].NoteID]
+		if !ok {
+			return fail(fmt.Errorf("❌ New note %s is missing from the index", created[n].NoteID))
+		}
+		if err := writeNoteBody(&zettels[ni], func(frontMatter *internal.FrontMatter, _ string) string {
+			frontMatter.Links = internal.MergeLinks(frontMatter.Links, internal.PlainLinks(original.NoteID)...)
+			return fmt.Sprintf("## %s\n\n%s\n\n%s", section.Title, section.Content(body), backlink)
+		}); err != nil {
+			return fail(err)
+		}
+		created[n] = zettels[ni]
+		replacements = append(replacements, fmt.Sprintf("- [%s](%s.md)", section.Title, created[n].NoteID))
+	}
+
+	if err := writeNoteBody(&zettels[oi], func(frontMatter *internal.FrontMatter, body string) string {
+		for _, note := range created {
+			frontMatter.Links = internal.MergeLinks(frontMatter.Links, internal.PlainLinks(note.NoteID)...)
+		}
+		return internal.ReplaceSections(body, chosen, replacements)
+	}); err != nil {
+		return fail(err)
+	}
+	return zettels, created, nil
+}
+
+var splitCmd = &cobra.Command{
+	Use:   "split [id]",
+	Short: "Split sections of a note into new notes",
+	Long: `Turn heading sections of a note into notes of their own. Each new note is
+titled after its heading, keeps the tags of the original and links back to it;
+the section in the original is replaced by a link to the new note. Links to
+a moved section (NoteID.md#heading, [[NoteID#Heading]]) are pointed at the new
+note. When the original has a sequence position, the new notes branch off it.
+
+The new notes and the changed files are previewed before anything is written.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if splitType != "" {
+			if err := validateNoteType(splitType); err != nil {
+				log.Printf("❌ Error: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		oi, err := internal.ResolveNote(zettels, args[0])
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		original := zettels[oi]
+
+		content, err := os.ReadFile(original.NotePath)
+		if err != nil {
+			log.Printf("❌ Failed to read note: %v", err)
+			os.Exit(1)
+		}
+		_, body, err := internal.ParseFrontMatter(string(content))
+		if err != nil {
+			log.Printf("❌ Failed to parse front matter: %v", err)
+			os.Exit(1)
+		}
+
+		sections := internal.NoteSections(body, original.Title)
+		if len(sections) == 0 {
+			log.Printf("⚠️ [%s] %s has no sections to split", original.ID, original.Title)
+			return
+		}
+		chosen, err := chooseSections(sections)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+		if len(chosen) == 0 {
+			log.Println("⚠️ No sections selected")
+			return
+		}
+
+		noteType := splitType
+		if noteType == "" {
+			noteType = original.NoteType
+		}
+
+		// Sequence positions of the new notes, branching off the original
+		branches := make([]string, len(chosen))
+		sequence := internal.NormalizeSequence(original.Sequence)
+		if sequence != "" && internal.ValidateSequence(sequence) == nil {
+			taken := append([]internal.Zettel{}, zettels...)
+			for n := range chosen {
+				branches[n] = internal.NextSequenceBranch(sequence, taken)
+				taken = append(taken, internal.Zettel{Sequence: branches[n]})
+			}
+		}
+
+		planned := planSectionLinks(zettels, original, chosen)
+
+		// Preview
+		noteStyle := color.New(color.FgHiCyan, color.Bold).SprintFunc()
+		lineStyle := color.New(color.FgHiBlack).SprintFunc()
+		added := color.New(color.FgGreen).SprintFunc()
+		removed := color.New(color.FgRed).SprintFunc()
+		for n, section := range chosen {
+			fmt.Printf("%s\n", noteStyle(fmt.Sprintf("[new] %s", section.Title)))
+			fmt.Printf("  %s\n", added(fmt.Sprintf("+ %d lines from [%s] %s", len(strings.Split(section.Content(body), "\n")), original.ID, original.Title)))
+			if branches[n] != "" {
+				fmt.Printf("  %s\n", added("+ sequence: "+branches[n]))
+			}
+		}
+		fmt.Printf("%s\n", noteStyle(fmt.Sprintf("[%s] %s", original.ID, original.NotePath)))
+		for _, section := range chosen {
+			fmt.Printf("  %s\n", removed(fmt.Sprintf("- %s %s", strings.Repeat("#", section.Level), section.Title)))
+			fmt.Printf("  %s\n", added(fmt.Sprintf("+ - [%s](new note)", section.Title)))
+		}
+		for _, note := range planned {
+			z := zettels[note.index]
+			fmt.Printf("%s\n", noteStyle(fmt.Sprintf("[%s] %s", z.ID, z.NotePath)))
+			for n, change := range note.changes {
+				fmt.Printf("  %s %s\n", lineStyle(fmt.Sprintf("%d:", change.Line)), removed("- "+strings.TrimSpace(change.Before)))
+				fmt.Printf("  %s %s\n", lineStyle(strings.Repeat(" ", len(fmt.Sprint(change.Line))+1)), added("+ → "+note.targets[n]))
+			}
+		}
+
+		if splitDryRun {
+			return
+		}
+		if !splitYes {
+			if !internal.IsInteractive() {
+				log.Println("❌ Confirming the split requires a terminal; use --yes to apply it")
+				os.Exit(1)
+			}
+			confirmed, err := confirmChanges()
+			if err != nil {
+				log.Printf("%v", err)
+				os.Exit(1)
+			}
+			if !confirmed {
+				log.Println("⚠️ Split cancelled")
+				return
+			}
+		}
+
+		zettels, created, err := splitNote(original, body, chosen, branches, noteType, config)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+		indexOf := make(map[string]int)
+		for i, z := range zettels {
+			indexOf[z.NoteID] = i
+		}
+
+		// Links to a moved section follow it to its new note
+		redirected := 0
+		for _, note := range planned {
+			i, ok := indexOf[note.noteID]
+			if !ok {
+				continue
+			}
+			noteContent, err := os.ReadFile(zettels[i].NotePath)
+			if err != nil {
+				log.Printf("❌ Failed to read %s: %v", zettels[i].NotePath, err)
+				continue
+			}
+			updated := string(noteContent)
+			for n, section := range chosen {
+				updated, _ = internal.RedirectSectionLinks(updated, original, section.Title, created[n])
+			}
+			_, updatedBody, err := internal.ParseFrontMatter(updated)
+			if err != nil {
+				log.Printf("❌ Failed to parse front matter of %s: %v", zettels[i].NotePath, err)
+				continue
+			}
+			if err := writeNoteBody(&zettels[i], func(_ *internal.FrontMatter, _ string) string {
+				return updatedBody
+			}); err != nil {
+				log.Printf("%v", err)
+				continue
+			}
+			redirected++
+		}
+
+		if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+			log.Printf("❌ Error updating JSON: %v", err)
+			os.Exit(1)
+		}
+
+		for _, note := range created {
+			log.Printf("✅ Split [%s] %s off [%s] %s", note.ID, note.Title, original.ID, original.Title)
+		}
+		if redirected > 0 {
+			log.Printf("✅ Section links updated in %d notes", redirected)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	splitCmd.Flags().StringSliceVarP(&splitHeadings, "heading", "H", []string{}, "Titles of the sections to split")
+	splitCmd.Flags().BoolVar(&splitAll, "all", false, "Split every section")
+	splitCmd.Flags().StringVarP(&splitType, "type", "t", "", "Type of the new notes (default: the type of the original)")
+	splitCmd.Flags().BoolVarP(&splitYes, "yes", "y", false, "Apply the split without asking")
+	splitCmd.Flags().BoolVar(&splitDryRun, "dry-run", false, "Only preview the changes")
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nakachan-ing/Zettelkasten-cli/internal"
@@ -60,8 +61,8 @@ func scanDirectory(dir string, sortAscending bool) (map[string]string, error) {
 	})
 
 	for _, info := range fileInfos {
-		noteID := info.Name()
-		noteFiles[noteID] = filepath.Join(dir, noteID)
+		noteID := strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
+		noteFiles[noteID] = filepath.Join(dir, info.Name())
 	}
 
 	return noteFiles, nil
@@ -96,7 +97,7 @@ func syncZettelData(zettels []internal.Zettel, notesDir, archiveDir, trashDir st
 			updatedZettels = append(updatedZettels, z)
 			existingNotes[z.NoteID] = true
 		} else {
-			newPath := filepath.Join(trashDir, z.NoteID)
+			newPath := filepath.Join(trashDir, z.NoteID+".md")
 			if err := os.Rename(z.NotePath, newPath); err != nil {
 				log.Printf("❌ Failed to move file to trash: %s (%v)", z.NotePath, err)
 				continue
@@ -133,19 +134,14 @@ func syncZettelData(zettels []internal.Zettel, notesDir, archiveDir, trashDir st
 				}
 
 				newZettel := internal.Zettel{
-					ID:         strconv.Itoa(newID),
-					NoteID:     frontMatter.ID,
-					Title:      frontMatter.Title,
-					NoteType:   frontMatter.Type,
-					Tags:       frontMatter.Tags,
-					TaskStatus: frontMatter.TaskStatus,
-					Links:      frontMatter.Links,
-					CreatedAt:  frontMatter.CreatedAt,
-					UpdatedAt:  frontMatter.UpdatedAt,
-					NotePath:   path,
-					Archived:   fileSet.archived,
-					Deleted:    fileSet.deleted,
+					ID:        strconv.Itoa(newID),
+					NoteID:    frontMatter.ID,
+					CreatedAt: frontMatter.CreatedAt,
+					NotePath:  path,
+					Archived:  fileSet.archived,
+					Deleted:   fileSet.deleted,
 				}
+				newZettel.CopyFrontMatter(frontMatter)
 				updatedZettels = append(updatedZettels, newZettel)
 				newID++
 			}
@@ -195,6 +191,15 @@ var syncCmd = &cobra.Command{
 		err = syncZettel(*config, zettels)
 		if err != nil {
 			log.Printf("❌ Error during sync: %v", err)
+			return
+		}
+
+		// Regenerate materialised saved queries against the synced index
+		if err := refreshMaterializedQueries(*config); err != nil {
+			log.Printf("❌ Error refreshing queries: %v", err)
+		}
+		if err := refreshIndexNotes(*config); err != nil {
+			log.Printf("❌ Error refreshing index notes: %v", err)
 		}
 	},
 }
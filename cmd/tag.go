@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+var tagsSort string
+var tagsTree bool
+
+// Replace a tag (and its sub-tags) in the front matter of every note and in the index
+func rewriteTag(oldTag, newTag string, config *internal.Config) (int, error) {
+	zettels, err := internal.LoadJson(*config)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Error loading JSON: %w", err)
+	}
+
+	updated := 0
+	for i := range zettels {
+		tags, changed := internal.ReplaceTag(zettels[i].Tags, oldTag, newTag)
+		if !changed {
+			continue
+		}
+
+		noteByte, err := os.ReadFile(zettels[i].NotePath)
+		if err != nil {
+			log.Printf("❌ Error reading note file: %v", err)
+			continue
+		}
+
+		frontMatter, body, err := internal.ParseFrontMatter(string(noteByte))
+		if err != nil {
+			log.Printf("❌ Error parsing front matter: %s (%v)", zettels[i].NotePath, err)
+			continue
+		}
+
+		frontMatter.Tags, _ = internal.ReplaceTag(frontMatter.Tags, oldTag, newTag)
+		frontMatter.UpdatedAt = time.Now().Format(internal.TimestampLayout)
+		updatedMarkdown := internal.UpdateFrontMatter(&frontMatter, body)
+
+		if err := os.WriteFile(zettels[i].NotePath, []byte(updatedMarkdown), 0644); err != nil {
+			log.Printf("❌ Error writing updated note: %v", err)
+			continue
+		}
+
+		zettels[i].Tags = tags
+		zettels[i].UpdatedAt = frontMatter.UpdatedAt
+		updated++
+	}
+
+	if updated == 0 {
+		return 0, nil
+	}
+	if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+		return 0, fmt.Errorf("❌ Error updating JSON file: %w", err)
+	}
+	return updated, nil
+}
+
+// Print the tag hierarchy with tree branches and the number of notes below each tag
+func printTagTree(nodes []*internal.TagNode, indent string, root bool) {
+	tagStyle := color.New(color.FgHiCyan, color.Bold).SprintFunc()
+	metaStyle := color.New(color.FgHiBlack).SprintFunc()
+
+	for i, node := range nodes {
+		branch, childIndent := "├── ", indent+"│   "
+		if i == len(nodes)-1 {
+			branch, childIndent = "└── ", indent+"    "
+		}
+		if root {
+			branch, childIndent = "", ""
+		}
+
+		counts := fmt.Sprintf("(%d)", node.Total)
+		if len(node.Children) > 0 && node.Count > 0 {
+			counts = fmt.Sprintf("(%d, %d tagged %s)", node.Total, node.Count, node.Path)
+		}
+		fmt.Printf("%s%s %s\n", metaStyle(indent+branch), tagStyle(node.Name), metaStyle(counts))
+		printTagTree(node.Children, childIndent, false)
+	}
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List tags with their note counts",
+	Long: `List tags with the number of notes using them and when they were last used.
+
+With --tree, hierarchical tags (area/infra/k8s) are shown as a tree with
+the number of notes carrying each tag or a tag below it; project:<name>
+tags are shown below "project".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		if tagsTree {
+			roots := internal.TagTree(zettels)
+			if machineOutput() {
+				records := []internal.TagRecord{}
+				var collect func(nodes []*internal.TagNode)
+				collect = func(nodes []*internal.TagNode) {
+					for _, node := range nodes {
+						records = append(records, internal.TagRecord{Tag: node.Path, Count: node.Total, LastUsed: node.LastUsed})
+						collect(node.Children)
+					}
+				}
+				collect(roots)
+				printRecords(records)
+				return
+			}
+
+			if len(roots) == 0 {
+				log.Println("⚠️ No tags found.")
+				return
+			}
+			printTagTree(roots, "", true)
+			return
+		}
+
+		stats := internal.CollectTags(zettels)
+		switch tagsSort {
+		case "count":
+			// Already most used first
+		case "name":
+			sort.SliceStable(stats, func(i, j int) bool {
+				return strings.ToLower(stats[i].Tag) < strings.ToLower(stats[j].Tag)
+			})
+		case "recent":
+			sort.SliceStable(stats, func(i, j int) bool { return stats[i].LastUsed > stats[j].LastUsed })
+		default:
+			log.Printf("❌ Invalid sort %q: must be 'count', 'name' or 'recent'", tagsSort)
+			os.Exit(1)
+		}
+
+		if machineOutput() {
+			records := make([]internal.TagRecord, 0, len(stats))
+			for _, stat := range stats {
+				records = append(records, internal.TagRecord{Tag: stat.Tag, Count: stat.Count, LastUsed: stat.LastUsed})
+			}
+			printRecords(records)
+			return
+		}
+
+		if len(stats) == 0 {
+			log.Println("⚠️ No tags found.")
+			return
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleDouble)
+		t.Style().Options.SeparateRows = false
+		t.AppendHeader(table.Row{"Tag", "Notes", "Last used"})
+		for _, stat := range stats {
+			t.AppendRow(table.Row{stat.Tag, stat.Count, stat.LastUsed})
+		}
+		t.Render()
+	},
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage tags",
+}
+
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename [old] [new]",
+	Short: "Rename a tag across all notes",
+	Long: `Rename a tag in the front matter of every note and in the index.
+Sub-tags follow their parent: renaming lang to language also turns
+lang/go into language/go.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		oldTag := strings.TrimSpace(args[0])
+		newTag := strings.TrimSpace(args[1])
+		if newTag == "" {
+			log.Printf("❌ The new tag must not be empty")
+			os.Exit(1)
+		}
+		if err := internal.ValidateTagMove(oldTag, newTag); err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		// Renaming onto a tag in use would silently merge them; only a change of case is allowed
+		if !strings.EqualFold(oldTag, newTag) && internal.TagInUse(zettels, newTag) {
+			log.Printf("❌ Tag %s is already in use. Use `zk tag merge %s %s` to merge them.", newTag, oldTag, newTag)
+			os.Exit(1)
+		}
+
+		updated, err := rewriteTag(oldTag, newTag, config)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+		if updated == 0 {
+			log.Printf("⚠️ Tag %s not found", oldTag)
+			return
+		}
+
+		log.Printf("✅ Tag %s renamed to %s (%d notes updated)", oldTag, newTag, updated)
+	},
+}
+
+var tagMergeCmd = &cobra.Command{
+	Use:   "merge [from] [into]",
+	Short: "Merge a tag into another across all notes",
+	Long: `Replace a tag with another tag that is already in use, in the front matter
+of every note and in the index. Sub-tags are merged too (from/x becomes into/x).`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		fromTag := strings.TrimSpace(args[0])
+		intoTag := strings.TrimSpace(args[1])
+		if strings.EqualFold(fromTag, intoTag) {
+			log.Printf("❌ Cannot merge a tag into itself")
+			os.Exit(1)
+		}
+		if err := internal.ValidateTagMove(fromTag, intoTag); err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		for _, tag := range []string{fromTag, intoTag} {
+			if !internal.TagInUse(zettels, tag) {
+				log.Printf("❌ Tag %s not found", tag)
+				os.Exit(1)
+			}
+		}
+
+		updated, err := rewriteTag(fromTag, intoTag, config)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
+		log.Printf("✅ Tag %s merged into %s (%d notes updated)", fromTag, intoTag, updated)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagRenameCmd)
+	tagCmd.AddCommand(tagMergeCmd)
+	tagsCmd.Flags().StringVar(&tagsSort, "sort", "count", "Sort tags by 'count', 'name' or 'recent'")
+	tagsCmd.Flags().BoolVar(&tagsTree, "tree", false, "Show hierarchical tags as a tree")
+}
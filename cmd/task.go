@@ -16,50 +16,71 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-var taskStatus string
+var taskStatuses []string
 var taskProject string
 var taskSortField string
 var taskTags []string
+var taskTagMode string
 var taskPageSize int
-
-func createNewTask(taskTitle, projectName string, config internal.Config) (string, internal.Zettel, error) {
+var taskDue string
+var taskPriority string
+var taskRecur string
+var taskOverdue bool
+var taskDueBefore string
+var taskTrash bool
+var taskArchive bool
+var taskInline bool
+
+func createNewTask(taskTitle, projectName, due, priority, recur string, config internal.Config) (string, internal.Zettel, error) {
 	t := time.Now()
 	noteId := t.Format("20060102150405")
 	createdAt := t.Format("2006-01-02 15:04:05")
 
-	tags := []string{fmt.Sprintf("project:%s", strings.ReplaceAll(projectName, " ", "_"))}
+	tags := []string{internal.ProjectTag(projectName)}
 
 	frontMatter := internal.FrontMatter{
 		ID:         noteId,
 		Title:      taskTitle,
 		Type:       "task",
 		Tags:       tags,
-		TaskStatus: "Not started",
+		TaskStatus: config.TaskStatuses()[0],
+		Due:        due,
+		Priority:   priority,
+		Recur:      recur,
 		CreatedAt:  createdAt,
 		UpdatedAt:  createdAt,
 	}
 
+	return writeNewTask(frontMatter, fmt.Sprintf("## %s", frontMatter.Title), config)
+}
+
+// Write a new task note and register it in the index
+func writeNewTask(frontMatter internal.FrontMatter, body string, config internal.Config) (string, internal.Zettel, error) {
 	frontMatterBytes, err := yaml.Marshal(frontMatter)
 	if err != nil {
 		return "", internal.Zettel{}, fmt.Errorf("❌ Failed to convert to YAML: %w", err)
 	}
 
-	content := fmt.Sprintf("---\n%s---\n\n## %s", string(frontMatterBytes), frontMatter.Title)
+	content := fmt.Sprintf("---\n%s---\n\n%s", string(frontMatterBytes), body)
 
-	filePath := fmt.Sprintf("%s/%s.md", config.NoteDir, noteId)
+	filePath := fmt.Sprintf("%s/%s.md", config.NoteDir, frontMatter.ID)
 	err = os.WriteFile(filePath, []byte(content), 0644)
 	if err != nil {
 		return "", internal.Zettel{}, fmt.Errorf("❌ Failed to create file: %w", err)
 	}
 
 	zettel := internal.Zettel{
-		NoteID:     noteId,
+		NoteID:     frontMatter.ID,
 		NoteType:   "task",
-		Title:      taskTitle,
-		Tags:       tags,
-		TaskStatus: "Not started",
-		CreatedAt:  createdAt,
-		UpdatedAt:  createdAt,
+		Title:      frontMatter.Title,
+		Tags:       frontMatter.Tags,
+		TaskStatus: frontMatter.TaskStatus,
+		Due:        frontMatter.Due,
+		Priority:   frontMatter.Priority,
+		Recur:      frontMatter.Recur,
+		Links:      frontMatter.Links,
+		CreatedAt:  frontMatter.CreatedAt,
+		UpdatedAt:  frontMatter.UpdatedAt,
 		NotePath:   filePath,
 	}
 
@@ -72,6 +93,73 @@ func createNewTask(taskTitle, projectName string, config internal.Config) (strin
 	return filePath, zettel, nil
 }
 
+// Create the next instance of a recurring task, linked back to the completed one
+func spawnNextTask(prev internal.Zettel, config internal.Config) (string, internal.Zettel, error) {
+	t := time.Now()
+
+	recur := internal.AnchorRecurrence(prev.Recur, prev.Due)
+	nextDue, err := internal.NextDueDate(recur, prev.Due, t)
+	if err != nil {
+		return "", internal.Zettel{}, err
+	}
+	recur = internal.AnchorRecurrence(recur, nextDue)
+
+	// Note IDs have a resolution of one second: a task completed in the
+	// second it was created waits for the next one
+	noteId := t.Format("20060102150405")
+	if noteId == prev.NoteID {
+		time.Sleep(time.Until(t.Truncate(time.Second).Add(time.Second)))
+		t = time.Now()
+		noteId = t.Format("20060102150405")
+	}
+	if noteId <= prev.NoteID {
+		return "", internal.Zettel{}, fmt.Errorf("❌ Note ID %s is already taken", noteId)
+	}
+	createdAt := t.Format("2006-01-02 15:04:05")
+
+	frontMatter := internal.FrontMatter{
+		ID:         noteId,
+		Title:      prev.Title,
+		Type:       "task",
+		Tags:       prev.Tags,
+		Links:      internal.PlainLinks(prev.NoteID),
+		TaskStatus: config.TaskStatuses()[0],
+		Due:        nextDue,
+		Priority:   prev.Priority,
+		Recur:      recur,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+
+	body := fmt.Sprintf("## %s\n\n### Links\n- [%s](%s.md)", prev.Title, prev.Title, prev.NoteID)
+	return writeNewTask(frontMatter, body, config)
+}
+
+// Change the status of a task in both its front matter and the index entry
+func updateTaskStatus(task *internal.Zettel, status string, config internal.Config) error {
+	note, err := os.ReadFile(task.NotePath)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+
+	frontMatter, body, err := internal.ParseFrontMatter(string(note))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse front matter: %w", err)
+	}
+
+	internal.SetTaskStatus(&frontMatter, status, config.TaskDoneStatus(), time.Now())
+	updatedContent := internal.UpdateFrontMatter(&frontMatter, body)
+
+	if err := os.WriteFile(task.NotePath, []byte(updatedContent), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write updated note: %w", err)
+	}
+
+	task.TaskStatus = frontMatter.TaskStatus
+	task.CompletedAt = frontMatter.CompletedAt
+	task.UpdatedAt = frontMatter.UpdatedAt
+	return nil
+}
+
 var taskCmd = &cobra.Command{
 	Use:     "task",
 	Short:   "Manage tasks",
@@ -93,7 +181,29 @@ var taskAddCmd = &cobra.Command{
 			return
 		}
 
-		newTaskStr, _, err := createNewTask(taskTitle, projectName, *config)
+		due, err := internal.ParseDueDate(taskDue, time.Now())
+		if err != nil {
+			log.Printf("❌ Error: %v", err)
+			return
+		}
+
+		priority, err := internal.NormalizeTaskPriority(taskPriority)
+		if err != nil {
+			log.Printf("❌ Error: %v", err)
+			return
+		}
+
+		recur := ""
+		if taskRecur != "" {
+			rule, err := internal.ParseRecurrence(taskRecur)
+			if err != nil {
+				log.Printf("❌ Error: %v", err)
+				return
+			}
+			recur = internal.AnchorRecurrence(rule.String(), due)
+		}
+
+		newTaskStr, _, err := createNewTask(taskTitle, projectName, due, priority, recur, *config)
 		if err != nil {
 			log.Printf("❌ Failed to create task: %v", err)
 			return
@@ -112,38 +222,113 @@ var taskAddCmd = &cobra.Command{
 	},
 }
 
+// Change a task's status, save the index and spawn the next instance of a recurring task
+func changeTaskStatus(tasks []internal.Zettel, i int, status string, config internal.Config) (*internal.Zettel, error) {
+	completed := status == config.TaskDoneStatus() && tasks[i].TaskStatus != status
+
+	if err := updateTaskStatus(&tasks[i], status, config); err != nil {
+		return nil, err
+	}
+
+	if err := internal.SaveUpdatedJson(tasks, &config); err != nil {
+		return nil, err
+	}
+
+	// Completing a task again (Done → In progress → Done) keeps its first successor
+	if !completed || tasks[i].Recur == "" || internal.HasNextInstance(tasks[i].Links) {
+		return nil, nil
+	}
+
+	_, next, err := spawnNextTask(tasks[i], config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to create next recurring task: %w", err)
+	}
+	if err := linkNextInstance(&tasks[i], next, config); err != nil {
+		return &next, err
+	}
+	return &next, nil
+}
+
+// Link a completed recurring task to its next instance, in its front matter
+// and in the index (reloaded, as it now holds the new instance)
+func linkNextInstance(task *internal.Zettel, next internal.Zettel, config internal.Config) error {
+	note, err := os.ReadFile(task.NotePath)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+	frontMatter, body, err := internal.ParseFrontMatter(string(note))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse front matter: %w", err)
+	}
+	frontMatter.Links = internal.MergeLinks(frontMatter.Links, internal.Link{To: next.NoteID, Rel: internal.NextInstanceRel})
+	if err := os.WriteFile(task.NotePath, []byte(internal.UpdateFrontMatter(&frontMatter, body)), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write updated note: %w", err)
+	}
+	task.Links = frontMatter.Links
+
+	zettels, err := internal.LoadJson(config)
+	if err != nil {
+		return err
+	}
+	for i := range zettels {
+		if zettels[i].NoteID == task.NoteID {
+			zettels[i].Links = frontMatter.Links
+		}
+	}
+	return internal.SaveUpdatedJson(zettels, &config)
+}
+
 var taskStatusCmd = &cobra.Command{
 	Use:     "status [id] [status]",
 	Short:   "Change task status",
+	Args:    cobra.ExactArgs(2),
 	Aliases: []string{"st"},
 	Run: func(cmd *cobra.Command, args []string) {
-		taskId := args[0]
-		status := args[1]
-
 		config, err := internal.LoadConfig()
 		if err != nil {
 			log.Printf("❌ Error loading config: %v", err)
 			return
 		}
 
+		status, err := internal.NormalizeTaskStatus(args[1], config.TaskStatuses())
+		if err != nil {
+			log.Printf("❌ Error: %v", err)
+			return
+		}
+
 		tasks, err := internal.LoadJson(*config)
 		if err != nil {
 			log.Printf("❌ Error loading JSON: %v", err)
 			return
 		}
 
+		// Resolved among every note, so that a note that is not a task is reported as such
+		taskId, err := resolveNoteID(tasks, args[0])
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
 		found := false
 		for i := range tasks {
 			if taskId == tasks[i].ID {
 				found = true
-				tasks[i].TaskStatus = status
 
-				if err := internal.SaveUpdatedJson(tasks, config); err != nil {
-					log.Printf("❌ Error updating JSON: %v", err)
+				if tasks[i].NoteType != "task" {
+					log.Printf("⚠️ Note %s is not a task", taskId)
+					return
+				}
+
+				next, err := changeTaskStatus(tasks, i, status, *config)
+				if err != nil {
+					log.Printf("❌ Error updating task: %v", err)
 					return
 				}
 
 				log.Printf("✅ Task %s status updated to: %s", taskId, status)
+				if next != nil {
+					log.Printf("🔁 Next %q is due on %s", next.Title, next.Due)
+				}
 				break
 			}
 		}
@@ -154,12 +339,85 @@ var taskStatusCmd = &cobra.Command{
 	},
 }
 
-var taskListCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List tasks",
-	Aliases: []string{"ls"},
+// Colour a task status for table output
+func colorTaskStatus(status string) string {
+	switch status {
+	case "In progress":
+		return text.FgHiBlue.Sprint(status)
+	case "Waiting":
+		return text.FgHiYellow.Sprint(status)
+	case "Done":
+		return text.FgHiMagenta.Sprint(status)
+	case "On hold":
+		return text.FgHiGreen.Sprint(status)
+	default:
+		return status
+	}
+}
+
+// List Markdown checkboxes found in note bodies
+func listInlineTasks(zettels []internal.Zettel) {
+	records := []internal.CheckboxRecord{}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleDouble)
+	t.Style().Options.SeparateRows = false
+	t.AppendHeader(table.Row{"Ref", "Note", "Task", "Done"})
+
+	count := 0
+	for _, zettel := range zettels {
+		if zettel.Deleted != taskTrash || (taskArchive && !zettel.Archived) {
+			continue
+		}
+		if taskProject != "" && !internal.InProject(zettel, taskProject) {
+			continue
+		}
+
+		checkboxes, err := internal.LoadCheckboxes(zettel.NotePath)
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			continue
+		}
+
+		for _, checkbox := range checkboxes {
+			done := "⬜"
+			if checkbox.Checked {
+				done = "✅"
+			}
+			ref := fmt.Sprintf("%s:%d", zettel.ID, checkbox.Line)
+			records = append(records, internal.CheckboxRecord{
+				Ref: ref, ID: zettel.ID, NoteID: zettel.NoteID, Title: zettel.Title,
+				Line: checkbox.Line, Text: checkbox.Text, Checked: checkbox.Checked,
+			})
+			t.AppendRow(table.Row{ref, zettel.Title, checkbox.Text, done})
+			count++
+		}
+	}
+
+	if machineOutput() {
+		printRecords(records)
+		return
+	}
+
+	if count == 0 {
+		log.Println("⚠️ No checkboxes found.")
+		return
+	}
+
+	log.Printf("📋 Displaying %d checkboxes\n", count)
+	t.Render()
+}
+
+var taskCheckCmd = &cobra.Command{
+	Use:   "check [note-id]:[line]",
+	Short: "Toggle a checkbox inside a note",
+	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		log.Println("🔍 Fetching task list...")
+		noteRef, line, err := internal.ParseCheckboxRef(args[0])
+		if err != nil {
+			log.Printf("❌ Error: %v", err)
+			return
+		}
 
 		config, err := internal.LoadConfig()
 		if err != nil {
@@ -167,75 +425,111 @@ var taskListCmd = &cobra.Command{
 			return
 		}
 
-		tasks, err := internal.LoadJson(*config)
+		zettels, err := internal.LoadJson(*config)
 		if err != nil {
 			log.Printf("❌ Error loading JSON: %v", err)
 			return
 		}
 
-		filteredTasks := []table.Row{}
+		noteId, err := resolveNoteID(zettels, noteRef)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
 
-		for _, task := range tasks {
-			// Apply delete filter
-			if trash && !task.Deleted {
+		for i := range zettels {
+			if noteId != zettels[i].ID {
 				continue
 			}
-			// Apply archive filter
-			if archive && !task.Archived {
-				continue
+
+			content, err := os.ReadFile(zettels[i].NotePath)
+			if err != nil {
+				log.Printf("❌ Error reading note file: %v", err)
+				return
 			}
-			if !trash && !archive {
-				if task.Deleted || task.NoteType != "task" {
-					continue
-				}
 
-				// Filter by status
-				if taskStatus != "" && strings.ToLower(task.TaskStatus) != strings.ToLower(taskStatus) {
-					continue
-				}
+			updatedContent, checkbox, err := internal.ToggleCheckbox(string(content), line)
+			if err != nil {
+				log.Printf("%v", err)
+				return
+			}
 
-				// Filter by project
-				if taskProject != "" {
-					matchProject := false
-					normalizedProject := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(taskProject), " ", "_"))
-					for _, tag := range task.Tags {
-						normalizedTag := strings.ToLower(strings.TrimSpace(tag))
-						if strings.HasPrefix(normalizedTag, "project:") {
-							projectName := strings.TrimSpace(normalizedTag[len("project:"):])
-							projectName = strings.ReplaceAll(projectName, " ", "_")
-							if projectName == normalizedProject {
-								matchProject = true
-								break
-							}
-						}
-					}
-					if !matchProject {
-						continue
-					}
-				}
+			updatedAt := time.Now().Format(internal.TimestampLayout)
+			if updatedContent, err = internal.TouchUpdatedAt(updatedContent, updatedAt); err != nil {
+				log.Printf("%v", err)
+				return
+			}
 
-				// Filter by tags
-				if len(taskTags) > 0 {
-					match := false
-					for _, filterTag := range taskTags {
-						for _, t := range task.Tags {
-							if strings.Contains(strings.ToLower(strings.TrimSpace(t)), strings.ToLower(strings.TrimSpace(filterTag))) {
-								match = true
-								break
-							}
-						}
-					}
-					if !match {
-						continue
-					}
-				}
+			if err := os.WriteFile(zettels[i].NotePath, []byte(updatedContent), 0644); err != nil {
+				log.Printf("❌ Error writing updated note: %v", err)
+				return
 			}
 
-			// Add to filtered list
-			filteredTasks = append(filteredTasks, table.Row{
-				task.ID, task.Title, task.TaskStatus, task.Tags,
-				task.CreatedAt, task.UpdatedAt,
-			})
+			zettels[i].UpdatedAt = updatedAt
+			if err := internal.SaveUpdatedJson(zettels, config); err != nil {
+				log.Printf("❌ Error updating JSON: %v", err)
+				return
+			}
+
+			state := "unchecked"
+			if checkbox.Checked {
+				state = "checked"
+			}
+			done, total := internal.CheckboxProgress(internal.ParseCheckboxes(updatedContent))
+			log.Printf("✅ %s:%d %s: %s (%s)", noteId, line, state, checkbox.Text, internal.FormatProgress(done, total))
+			return
+		}
+
+		log.Printf("⚠️ Note with ID %s not found", noteId)
+	},
+}
+
+var taskListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List tasks",
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Println("🔍 Fetching task list...")
+
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return
+		}
+
+		tasks, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			return
+		}
+
+		// List checkboxes inside notes instead of task notes
+		if taskInline {
+			listInlineTasks(tasks)
+			return
+		}
+
+		query := internal.TaskQuery{
+			Statuses:  taskStatuses,
+			Project:   taskProject,
+			Tags:      taskTags,
+			TagMode:   taskTagMode,
+			Overdue:   taskOverdue,
+			DueBefore: taskDueBefore,
+			Sort:      taskSortField,
+			Trash:     taskTrash,
+			Archive:   taskArchive,
+		}
+
+		filteredTasks, err := internal.QueryTasks(tasks, query, *config, time.Now())
+		if err != nil {
+			log.Printf("❌ Error: %v", err)
+			return
+		}
+
+		if machineOutput() {
+			printRecords(internal.NewNoteRecords(filteredTasks))
+			return
 		}
 
 		// No tasks found
@@ -247,20 +541,19 @@ var taskListCmd = &cobra.Command{
 		// Pagination
 		reader := bufio.NewReader(os.Stdin)
 		page := 0
+		now := time.Now()
 
 		log.Printf("📋 Displaying %d tasks\n", len(filteredTasks))
 
 		// `--limit` がない場合は全件表示
-		if pageSize == -1 {
+		pageSize := taskPageSize
+		if pageSize <= 0 || pageSize > len(filteredTasks) {
 			pageSize = len(filteredTasks)
 		}
 
 		for {
 			start := page * pageSize
 			end := start + pageSize
-			// if end > len(filteredTasks) {
-			// 	end = len(filteredTasks)
-			// }
 
 			// 範囲チェック
 			if start >= len(filteredTasks) {
@@ -276,31 +569,23 @@ var taskListCmd = &cobra.Command{
 			t.SetStyle(table.StyleDouble)
 			t.Style().Options.SeparateRows = false
 
-			t.AppendHeader(table.Row{"ID", "Title", "Status", "Tags", "Created", "Updated"})
-			for _, row := range filteredTasks[start:end] {
-				status := row[2].(string)
-				var statusColored string
-
-				switch status {
-				case "Not started":
-					statusColored = status
-				case "In progress":
-					statusColored = text.FgHiBlue.Sprintf(status)
-				case "Waiting":
-					statusColored = text.FgHiYellow.Sprintf(status)
-				case "Done":
-					statusColored = text.FgHiMagenta.Sprintf(status)
-				case "On hold":
-					statusColored = text.FgHiGreen.Sprintf(status)
-				default:
-					statusColored = status
+			t.AppendHeader(table.Row{"ID", "Title", "Status", "Priority", "Due", "Checklist", "Tags", "Created", "Updated"})
+			for _, task := range filteredTasks[start:end] {
+				due := task.Due
+				if internal.IsOverdue(task, config.TaskDoneStatus(), now) {
+					due = text.FgHiRed.Sprint(due)
 				}
 
-				t.AppendRow(table.Row{row[0], row[1], statusColored, row[3], row[4], row[5]})
+				checklist := ""
+				if checkboxes, err := internal.LoadCheckboxes(task.NotePath); err == nil {
+					checklist = internal.FormatProgress(internal.CheckboxProgress(checkboxes))
+				}
+
+				t.AppendRow(table.Row{task.ID, task.Title, colorTaskStatus(task.TaskStatus), task.Priority, due, checklist, task.Tags, task.CreatedAt, task.UpdatedAt})
 			}
 			t.Render()
 
-			if pageSize == len(filteredTasks) {
+			if end >= len(filteredTasks) {
 				break
 			}
 
@@ -322,7 +607,22 @@ func init() {
 	taskCmd.AddCommand(taskAddCmd)
 	taskCmd.AddCommand(taskStatusCmd)
 	taskCmd.AddCommand(taskListCmd)
+	taskCmd.AddCommand(taskCheckCmd)
 	rootCmd.AddCommand(taskCmd)
 
+	taskAddCmd.Flags().StringVar(&taskDue, "due", "", "Set the due date (YYYY-MM-DD, today, tomorrow, +Nd, +Nw)")
+	taskAddCmd.Flags().StringVar(&taskPriority, "priority", "", "Set the priority (high, medium, low)")
+	taskAddCmd.Flags().StringVar(&taskRecur, "recur", "", "Repeat the task (daily, weekly on <weekday>, monthly on <day>, every N days)")
+
 	taskListCmd.Flags().IntVar(&taskPageSize, "limit", -1, "Set the number of notes to display per page (-1 for all)")
+	taskListCmd.Flags().StringSliceVar(&taskStatuses, "status", []string{}, "Filter by task status")
+	taskListCmd.Flags().StringVar(&taskProject, "project", "", "Filter by project")
+	taskListCmd.Flags().StringSliceVar(&taskTags, "tag", []string{}, "Filter by tags (also matching sub-tags; '=tag' for the tag alone)")
+	taskListCmd.Flags().StringVar(&taskTagMode, "tag-mode", internal.TagModeAny, "Match any or all of the --tag values (any, all)")
+	taskListCmd.Flags().BoolVar(&taskOverdue, "overdue", false, "Show only overdue tasks")
+	taskListCmd.Flags().StringVar(&taskDueBefore, "due-before", "", "Show only tasks due before a date (YYYY-MM-DD, today, tomorrow, +Nd, +Nw)")
+	taskListCmd.Flags().StringVar(&taskSortField, "sort", "", "Sort tasks by field (due, priority, status)")
+	taskListCmd.Flags().BoolVar(&taskTrash, "trash", false, "Show deleted tasks")
+	taskListCmd.Flags().BoolVar(&taskArchive, "archive", false, "Show archived tasks")
+	taskListCmd.Flags().BoolVar(&taskInline, "inline", false, "List Markdown checkboxes inside notes")
 }
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nakachan-ing/Zettelkasten-cli/internal"
+	"github.com/spf13/cobra"
+)
+
+// Write a sequence position to a note's front matter and index entry
+func setNoteSequence(zettel *internal.Zettel, sequence string) error {
+	content, err := os.ReadFile(zettel.NotePath)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read note: %w", err)
+	}
+
+	frontMatter, body, err := internal.ParseFrontMatter(string(content))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse front matter: %w", err)
+	}
+	frontMatter.Sequence = sequence
+	frontMatter.UpdatedAt = time.Now().Format(internal.TimestampLayout)
+
+	if err := os.WriteFile(zettel.NotePath, []byte(internal.UpdateFrontMatter(&frontMatter, body)), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write note: %w", err)
+	}
+	zettel.Sequence = sequence
+	zettel.UpdatedAt = frontMatter.UpdatedAt
+	return nil
+}
+
+// Sequence position of a new note continuing (`after`) or branching off (`branch`) a note.
+// A note without a sequence first gets the next top-level position.
+func nextSequence(after, branch string, config internal.Config) (string, error) {
+	zettels, err := internal.LoadJson(config)
+	if err != nil {
+		return "", fmt.Errorf("❌ Error loading JSON: %w", err)
+	}
+
+	ref := after
+	if ref == "" {
+		ref = branch
+	}
+	i, err := internal.ResolveNote(zettels, ref)
+	if err != nil {
+		return "", fmt.Errorf("❌ %w", err)
+	}
+	base := &zettels[i]
+
+	sequence := internal.NormalizeSequence(base.Sequence)
+	if sequence == "" {
+		sequence = internal.NextTopSequence(zettels)
+		if err := setNoteSequence(base, sequence); err != nil {
+			return "", err
+		}
+		if err := internal.SaveUpdatedJson(zettels, &config); err != nil {
+			return "", err
+		}
+		log.Printf("✅ Assigned sequence %s to [%s] %s", sequence, base.ID, base.Title)
+	} else if err := internal.ValidateSequence(sequence); err != nil {
+		return "", fmt.Errorf("❌ [%s] %s: %w", base.ID, base.Title, err)
+	}
+
+	if after != "" {
+		return internal.NextSequenceAfter(sequence, zettels), nil
+	}
+	return internal.NextSequenceBranch(sequence, zettels), nil
+}
+
+// Print the sequence hierarchy with tree branches
+func printSequenceTree(nodes []*internal.SequenceNode, indent string, root bool) {
+	sequenceStyle := color.New(color.FgHiYellow, color.Bold).SprintFunc()
+	typeStyle := color.New(color.FgHiGreen).SprintFunc()
+	metaStyle := color.New(color.FgHiBlack).SprintFunc()
+
+	for i, node := range nodes {
+		branch, childIndent := "├── ", indent+"│   "
+		if i == len(nodes)-1 {
+			branch, childIndent = "└── ", indent+"    "
+		}
+		if root {
+			branch, childIndent = "", ""
+		}
+
+		if len(node.Notes) == 0 {
+			fmt.Printf("%s%s %s\n", metaStyle(indent+branch), sequenceStyle(node.Sequence), metaStyle("(missing)"))
+		}
+		for n, note := range node.Notes {
+			label := fmt.Sprintf("%s [%s] %s", sequenceStyle(node.Sequence), note.ID, note.Title)
+			if note.NoteType != "" {
+				label += " " + typeStyle("("+note.NoteType+")")
+			}
+			if n > 0 {
+				label += " " + metaStyle("(duplicate position)")
+			}
+			fmt.Printf("%s%s\n", metaStyle(indent+branch), label)
+		}
+		printSequenceTree(node.Children, childIndent, false)
+	}
+}
+
+var treeCmd = &cobra.Command{
+	Use:   "tree [id]",
+	Short: "Show the Folgezettel sequence hierarchy",
+	Long: `Show the notes having a sequence position (sequence: 1a2 in the front matter)
+as a hierarchy in reading order, or only the branch of one note.
+
+Positions are assigned with zk new --after <id> and zk new --branch <id>.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := internal.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		zettels, err := internal.LoadJson(*config)
+		if err != nil {
+			log.Printf("❌ Error loading JSON: %v", err)
+			os.Exit(1)
+		}
+
+		active := []internal.Zettel{}
+		for _, zettel := range zettels {
+			if !zettel.Deleted && !zettel.Archived {
+				active = append(active, zettel)
+			}
+			if sequence := internal.NormalizeSequence(zettel.Sequence); sequence != "" {
+				if err := internal.ValidateSequence(sequence); err != nil {
+					log.Printf("⚠️ [%s] %s: %v", zettel.ID, zettel.Title, err)
+				}
+			}
+		}
+		roots := internal.SequenceTree(active)
+
+		if len(args) == 1 {
+			i, err := internal.ResolveNote(zettels, args[0])
+			if err != nil {
+				log.Printf("❌ %v", err)
+				os.Exit(1)
+			}
+			node := internal.FindSequenceNode(roots, internal.NormalizeSequence(zettels[i].Sequence))
+			if node == nil {
+				log.Printf("❌ Note [%s] %s has no sequence position", zettels[i].ID, zettels[i].Title)
+				os.Exit(1)
+			}
+			roots = []*internal.SequenceNode{node}
+		}
+
+		if machineOutput() {
+			records := []internal.NoteRecord{}
+			var collect func(nodes []*internal.SequenceNode)
+			collect = func(nodes []*internal.SequenceNode) {
+				for _, node := range nodes {
+					records = append(records, internal.NewNoteRecords(node.Notes)...)
+					collect(node.Children)
+				}
+			}
+			collect(roots)
+			printRecords(records)
+			return
+		}
+
+		if len(roots) == 0 {
+			log.Println("⚠️ No notes have a sequence position. Use `zk new --after <id>` or `--branch <id>`.")
+			return
+		}
+		printSequenceTree(roots, "", true)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(treeCmd)
+}
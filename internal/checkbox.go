@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GitHub-style checkbox (`- [ ]` / `- [x]`) inside a note body
+type Checkbox struct {
+	Line    int // 1-based line number in the note file
+	Text    string
+	Checked bool
+}
+
+var checkboxPattern = regexp.MustCompile(`^(\s*[-*+] \[)([ xX])(\]\s+)(.*)$`)
+
+// Iterate over body lines, skipping front matter and fenced code blocks
+func forEachBodyLine(lines []string, fn func(i int, line string)) {
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	forEachTextLine(lines[start:], func(i int, line string) {
+		fn(start+i, line)
+	})
+}
+
+// Iterate over the lines of a body without front matter, skipping fenced
+// code blocks (a leading `---` is a horizontal rule, not front matter)
+func forEachTextLine(lines []string, fn func(i int, line string)) {
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			fn(i, line)
+		}
+	}
+}
+
+// Parse checkboxes from note content
+func ParseCheckboxes(content string) []Checkbox {
+	var checkboxes []Checkbox
+	lines := strings.Split(content, "\n")
+
+	forEachBodyLine(lines, func(i int, line string) {
+		m := checkboxPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			return
+		}
+		checkboxes = append(checkboxes, Checkbox{
+			Line:    i + 1,
+			Text:    strings.TrimSpace(m[4]),
+			Checked: m[2] != " ",
+		})
+	})
+	return checkboxes
+}
+
+// Read a note and parse its checkboxes
+func LoadCheckboxes(notePath string) ([]Checkbox, error) {
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read note: %w", err)
+	}
+	return ParseCheckboxes(string(content)), nil
+}
+
+// Toggle the checkbox on a given line, returning the updated content
+func ToggleCheckbox(content string, line int) (string, Checkbox, error) {
+	for _, checkbox := range ParseCheckboxes(content) {
+		if checkbox.Line != line {
+			continue
+		}
+
+		lines := strings.Split(content, "\n")
+		mark := "x"
+		if checkbox.Checked {
+			mark = " "
+		}
+		lines[line-1] = checkboxPattern.ReplaceAllString(lines[line-1], "${1}"+mark+"${3}${4}")
+		checkbox.Checked = !checkbox.Checked
+		return strings.Join(lines, "\n"), checkbox, nil
+	}
+	return content, Checkbox{}, fmt.Errorf("❌ No checkbox found on line %d", line)
+}
+
+// Count completed and total checkboxes
+func CheckboxProgress(checkboxes []Checkbox) (int, int) {
+	done := 0
+	for _, checkbox := range checkboxes {
+		if checkbox.Checked {
+			done++
+		}
+	}
+	return done, len(checkboxes)
+}
+
+// Format a completion ratio such as `2/5 (40%)`
+func FormatProgress(done, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d (%d%%)", done, total, done*100/total)
+}
+
+// Parse a checkbox reference of the form `<note-id>:<line>`
+func ParseCheckboxRef(ref string) (string, int, error) {
+	i := strings.LastIndex(ref, ":")
+	if i <= 0 || i == len(ref)-1 {
+		return "", 0, fmt.Errorf("invalid checkbox reference %q: use <note-id>:<line>", ref)
+	}
+	line, err := strconv.Atoi(ref[i+1:])
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("invalid line number in %q", ref)
+	}
+	return ref[:i], line, nil
+}
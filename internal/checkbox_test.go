@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const checkboxNote = `---
+id: "20250301090000"
+title: Checklist
+tags: [home, chores]
+links: []
+created_at: "2025-03-01 09:00:00"
+updated_at: "2025-03-01 09:00:00"
+---
+
+## Checklist
+
+- [ ] Buy milk
+- [x] Pay rent
+  * [X] Nested item
+
+` + "```" + `
+- [ ] Not a task
+` + "```" + `
++ [ ]   Spaces trimmed
+- [] Not a checkbox
+`
+
+func TestParseCheckboxes(t *testing.T) {
+	want := []Checkbox{
+		{Line: 12, Text: "Buy milk"},
+		{Line: 13, Text: "Pay rent", Checked: true},
+		{Line: 14, Text: "Nested item", Checked: true},
+		{Line: 19, Text: "Spaces trimmed"},
+	}
+	if got := ParseCheckboxes(checkboxNote); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCheckboxes = %+v, want %+v", got, want)
+	}
+
+	done, total := CheckboxProgress(want)
+	if done != 2 || total != 4 || FormatProgress(done, total) != "2/4 (50%)" {
+		t.Errorf("progress = %d/%d (%q)", done, total, FormatProgress(done, total))
+	}
+	if FormatProgress(0, 0) != "" {
+		t.Errorf("FormatProgress(0, 0) = %q, want empty", FormatProgress(0, 0))
+	}
+}
+
+func TestToggleCheckbox(t *testing.T) {
+	tests := []struct {
+		line    int
+		want    string
+		checked bool
+		wantErr bool
+	}{
+		{line: 12, want: "- [x] Buy milk", checked: true},
+		{line: 13, want: "- [ ] Pay rent"},
+		{line: 14, want: "  * [ ] Nested item"},
+		{line: 17, wantErr: true}, // Inside a code block
+		{line: 20, wantErr: true},
+		{line: 2, wantErr: true}, // Front matter
+	}
+	for _, tt := range tests {
+		updated, checkbox, err := ToggleCheckbox(checkboxNote, tt.line)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("line %d: expected an error", tt.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("line %d: %v", tt.line, err)
+		}
+		if got := strings.Split(updated, "\n")[tt.line-1]; got != tt.want || checkbox.Checked != tt.checked {
+			t.Errorf("line %d: got %q (checked %v), want %q (checked %v)", tt.line, got, checkbox.Checked, tt.want, tt.checked)
+		}
+	}
+}
+
+// Toggling a checkbox and bumping updated_at must not move the other
+// checkboxes, whose refs (`<id>:<line>`) may already be on screen
+func TestToggleCheckboxKeepsLines(t *testing.T) {
+	before := ParseCheckboxes(checkboxNote)
+
+	updated, _, err := ToggleCheckbox(checkboxNote, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated, err = TouchUpdatedAt(updated, "2025-03-02 10:00:00"); err != nil {
+		t.Fatal(err)
+	}
+
+	after := ParseCheckboxes(updated)
+	if len(after) != len(before) {
+		t.Fatalf("got %d checkboxes, want %d", len(after), len(before))
+	}
+	for i := range before {
+		if after[i].Line != before[i].Line || after[i].Text != before[i].Text {
+			t.Errorf("checkbox %d moved: %+v → %+v", i, before[i], after[i])
+		}
+	}
+
+	// Every other line is left as written
+	want := strings.Replace(strings.Replace(checkboxNote, "- [ ] Buy milk", "- [x] Buy milk", 1),
+		`updated_at: "2025-03-01 09:00:00"`, `updated_at: "2025-03-02 10:00:00"`, 1)
+	if updated != want {
+		t.Errorf("content changed beyond the checkbox and updated_at:\n%s", updated)
+	}
+
+	// The next checkbox can be toggled by its old ref
+	if _, checkbox, err := ToggleCheckbox(updated, 13); err != nil || checkbox.Text != "Pay rent" {
+		t.Errorf("toggling 13 after 12: %+v, %v", checkbox, err)
+	}
+}
+
+func TestTouchUpdatedAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"replaced", "---\ntitle: a\nupdated_at: \"old\"\n---\nbody", "---\ntitle: a\nupdated_at: \"new\"\n---\nbody", false},
+		{"added", "---\ntitle: a\n---\nbody", "---\ntitle: a\nupdated_at: \"new\"\n---\nbody", false},
+		{"crlf", "---\r\nupdated_at: old\r\n---\r\n", "---\r\nupdated_at: \"new\"\r\n---\r\n", false},
+		{"body field untouched", "---\ntitle: a\n---\nupdated_at: x", "---\ntitle: a\nupdated_at: \"new\"\n---\nupdated_at: x", false},
+		{"no front matter", "body", "", true},
+		{"unterminated", "---\ntitle: a", "", true},
+	}
+	for _, tt := range tests {
+		got, err := TouchUpdatedAt(tt.content, "new")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v", tt.name, err)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseCheckboxRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		note    string
+		line    int
+		wantErr bool
+	}{
+		{"12:5", "12", 5, false},
+		{"Weekly review:3", "Weekly review", 3, false},
+		{"a:b:7", "a:b", 7, false},
+		{"12", "", 0, true},
+		{":5", "", 0, true},
+		{"12:", "", 0, true},
+		{"12:0", "", 0, true},
+		{"12:x", "", 0, true},
+	}
+	for _, tt := range tests {
+		note, line, err := ParseCheckboxRef(tt.ref)
+		if (err != nil) != tt.wantErr || note != tt.note || line != tt.line {
+			t.Errorf("ParseCheckboxRef(%q) = %q, %d, %v", tt.ref, note, line, err)
+		}
+	}
+}
@@ -16,7 +16,9 @@ type Config struct {
 	Editor     string `yaml:"editor"`
 	ZettelJson string `yaml:"zettel_json"`
 	ArchiveDir string `yaml:"archive_dir"`
-	Backup     struct {
+	// Saved queries file (defaults to `queries.yaml` next to `zettel_json`)
+	SavedQueries string `yaml:"saved_queries"`
+	Backup       struct {
 		Enable    bool   `yaml:"enable"`
 		Frequency int    `yaml:"frequency"`
 		Retention int    `yaml:"retention"`
@@ -27,6 +29,14 @@ type Config struct {
 		Retention int    `yaml:"retention"`
 		TrashDir  string `yaml:"trash_dir"`
 	}
+	Task struct {
+		Statuses   []string `yaml:"statuses"`
+		DoneStatus string   `yaml:"done_status"`
+	}
+	Search struct {
+		Backend      string `yaml:"backend"`
+		SnippetWidth int    `yaml:"snippet_width"`
+	}
 }
 
 func GetConfigPath() (string, error) {
@@ -104,6 +114,7 @@ func LoadConfig() (*Config, error) {
 	config.Backup.BackupDir = expandHomeDir(config.Backup.BackupDir)
 	config.ZettelJson = expandHomeDir(config.ZettelJson)
 	config.Trash.TrashDir = expandHomeDir(config.Trash.TrashDir)
+	config.SavedQueries = expandHomeDir(config.SavedQueries)
 
 	return &config, nil
 }
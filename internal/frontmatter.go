@@ -11,16 +11,22 @@ import (
 )
 
 type FrontMatter struct {
-	ID         string   `yaml:"id"`
-	Title      string   `yaml:"title"`
-	Type       string   `yaml:"type"`
-	Tags       []string `yaml:"tags"`
-	Links      []string `yaml:"links"`
-	TaskStatus string   `yaml:"task_status"`
-	CreatedAt  string   `yaml:"created_at"`
-	UpdatedAt  string   `yaml:"updated_at"`
-	Archived   bool     `yaml:"archived"`
-	Deleted    bool     `yaml:"deleted"`
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title"`
+	Type        string   `yaml:"type"`
+	Tags        []string `yaml:"tags"`
+	Aliases     []string `yaml:"aliases,omitempty"`
+	Sequence    string   `yaml:"sequence,omitempty"`
+	Links       Links    `yaml:"links"`
+	TaskStatus  string   `yaml:"task_status"`
+	Due         string   `yaml:"due,omitempty"`
+	Priority    string   `yaml:"priority,omitempty"`
+	CompletedAt string   `yaml:"completed_at,omitempty"`
+	Recur       string   `yaml:"recur,omitempty"`
+	CreatedAt   string   `yaml:"created_at"`
+	UpdatedAt   string   `yaml:"updated_at"`
+	Archived    bool     `yaml:"archived"`
+	Deleted     bool     `yaml:"deleted"`
 }
 
 // Parse front matter from note content
@@ -75,3 +81,30 @@ func SaveUpdatedJson(zettels []Zettel, config *Config) error {
 	log.Printf("✅ Successfully updated JSON file: %s", config.ZettelJson)
 	return nil
 }
+
+// Set `updated_at` in the front matter of note content, leaving every other
+// line as written so that body line numbers (checkbox refs) stay the same
+func TouchUpdatedAt(content, timestamp string) (string, error) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return content, fmt.Errorf("❌ Front matter not found")
+	}
+
+	field := fmt.Sprintf("updated_at: %q", timestamp)
+	for i := 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "---" {
+			// No field yet: add it at the end of the front matter
+			lines = append(lines[:i], append([]string{field}, lines[i:]...)...)
+			return strings.Join(lines, "\n"), nil
+		}
+		if strings.HasPrefix(lines[i], "updated_at:") {
+			if strings.HasSuffix(lines[i], "\r") {
+				field += "\r"
+			}
+			lines[i] = field
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+	return content, fmt.Errorf("❌ Invalid front matter format")
+}
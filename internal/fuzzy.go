@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Score how well a pattern matches a text as a case-insensitive subsequence.
+// Every space-separated word of the pattern must match; consecutive characters
+// and matches at word starts score higher.
+func FuzzyScore(pattern, text string) (int, bool) {
+	words := strings.Fields(strings.ToLower(pattern))
+	if len(words) == 0 {
+		return 0, true
+	}
+
+	target := []rune(strings.ToLower(text))
+	total := 0
+	for _, word := range words {
+		score, ok := fuzzyWordScore([]rune(word), target)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+func fuzzyWordScore(word, target []rune) (int, bool) {
+	// A plain substring match always beats a scattered one
+	text := string(target)
+	if i := strings.Index(text, string(word)); i >= 0 {
+		score := 100 + 10*len(word)
+		if i == 0 {
+			score += 50
+		} else if isWordBoundary(target, utf8.RuneCountInString(text[:i])) {
+			score += 25
+		}
+		return score, true
+	}
+
+	score := 0
+	pos := 0
+	prev := -2
+	for _, r := range word {
+		found := -1
+		for i := pos; i < len(target); i++ {
+			if target[i] == r {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, false
+		}
+
+		score++
+		if found == prev+1 {
+			score += 5
+		}
+		if isWordBoundary(target, found) {
+			score += 3
+		}
+		score -= min(found-pos, 5) // Penalise gaps
+		prev = found
+		pos = found + 1
+	}
+	return score, true
+}
+
+func isWordBoundary(target []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := target[i-1]
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev)
+}
@@ -0,0 +1,311 @@
+package internal
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Where a link between two notes was found
+const (
+	EdgeFrontMatter = "front-matter" // `links:` in the front matter
+	EdgeBody        = "body"         // Markdown or wiki link in the body
+	EdgeAutoLink    = "auto-link"    // Added by `zk link --auto`
+)
+
+// Note of the link graph
+type GraphNode struct {
+	NoteID   string   `json:"id"`
+	ID       string   `json:"short_id"`
+	Title    string   `json:"title"`
+	Type     string   `json:"type"`
+	Tags     []string `json:"tags"`
+	Projects []string `json:"projects"`
+}
+
+// Directed link between two notes of the graph (identified by note ID)
+type GraphEdge struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Sources []string `json:"sources"`
+	Rel     string   `json:"rel,omitempty"`  // Relation of a typed front matter link
+	Note    string   `json:"note,omitempty"` // Annotation of a front matter link
+}
+
+// Link graph of the notes
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+
+	index map[string]int
+}
+
+func newGraph(nodes []GraphNode, edges []GraphEdge) Graph {
+	g := Graph{Nodes: nodes, Edges: edges, index: make(map[string]int, len(nodes))}
+	for i, node := range nodes {
+		g.index[node.NoteID] = i
+	}
+	return g
+}
+
+// Look up a node by note ID
+func (g Graph) Node(noteID string) (GraphNode, bool) {
+	i, ok := g.index[noteID]
+	if !ok {
+		return GraphNode{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// Check whether the graph contains a note
+func (g Graph) HasNode(noteID string) bool {
+	_, ok := g.index[noteID]
+	return ok
+}
+
+// Outgoing links of each note, keyed by note ID
+func (g Graph) Outgoing() map[string][]GraphEdge {
+	out := make(map[string][]GraphEdge)
+	for _, edge := range g.Edges {
+		out[edge.From] = append(out[edge.From], edge)
+	}
+	return out
+}
+
+// Incoming links of each note, keyed by note ID
+func (g Graph) Incoming() map[string][]GraphEdge {
+	in := make(map[string][]GraphEdge)
+	for _, edge := range g.Edges {
+		in[edge.To] = append(in[edge.To], edge)
+	}
+	return in
+}
+
+// Neighbours of each note regardless of link direction, keyed by note ID
+func (g Graph) Neighbours() map[string][]string {
+	neighbours := make(map[string][]string)
+	seen := make(map[[2]string]bool)
+	add := func(a, b string) {
+		if a == b || seen[[2]string{a, b}] {
+			return
+		}
+		seen[[2]string{a, b}] = true
+		neighbours[a] = append(neighbours[a], b)
+	}
+	for _, edge := range g.Edges {
+		add(edge.From, edge.To)
+		add(edge.To, edge.From)
+	}
+	return neighbours
+}
+
+// Keep only the given notes and the links between them
+func (g Graph) Subgraph(keep func(node GraphNode) bool) Graph {
+	nodes := []GraphNode{}
+	kept := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if keep(node) {
+			nodes = append(nodes, node)
+			kept[node.NoteID] = true
+		}
+	}
+	edges := []GraphEdge{}
+	for _, edge := range g.Edges {
+		if kept[edge.From] && kept[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+	return newGraph(nodes, edges)
+}
+
+// Note IDs within `depth` links of a note, in either direction, with their distance
+func (g Graph) Distances(noteID string, depth int) map[string]int {
+	distances := map[string]int{noteID: 0}
+	if !g.HasNode(noteID) {
+		return distances
+	}
+	neighbours := g.Neighbours()
+	frontier := []string{noteID}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var next []string
+		for _, id := range frontier {
+			for _, neighbour := range neighbours[id] {
+				if _, seen := distances[neighbour]; !seen {
+					distances[neighbour] = d
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+	return distances
+}
+
+// Keep only the links with one of the relations ("none" keeps untyped links)
+func (g Graph) WithRelations(relations []string) Graph {
+	edges := []GraphEdge{}
+	for _, edge := range g.Edges {
+		for _, rel := range relations {
+			rel = NormalizeRelation(rel)
+			if edge.Rel == rel || (rel == "none" && edge.Rel == "") {
+				edges = append(edges, edge)
+				break
+			}
+		}
+	}
+	return newGraph(g.Nodes, edges)
+}
+
+// Subgraph of the notes within `depth` links of a note
+func (g Graph) Neighbourhood(noteID string, depth int) Graph {
+	distances := g.Distances(noteID, depth)
+	return g.Subgraph(func(node GraphNode) bool {
+		_, ok := distances[node.NoteID]
+		return ok
+	})
+}
+
+// Resolves link targets (note IDs, short IDs, file names, titles) to note IDs
+type linkResolver struct {
+	byNoteID map[string]string
+	byID     map[string]string
+	byName   map[string]string
+}
+
+func newLinkResolver(zettels []Zettel) linkResolver {
+	r := linkResolver{byNoteID: map[string]string{}, byID: map[string]string{}, byName: map[string]string{}}
+	for _, z := range zettels {
+		r.byNoteID[z.NoteID] = z.NoteID
+		r.byID[z.ID] = z.NoteID
+		for _, name := range NoteNames(z) {
+			if _, exists := r.byName[strings.ToLower(name)]; !exists {
+				r.byName[strings.ToLower(name)] = z.NoteID
+			}
+		}
+	}
+	return r
+}
+
+// Resolve a `links:` entry (a note ID, or a short ID as written by older versions)
+func (r linkResolver) frontMatterLink(link string) (string, bool) {
+	link = strings.TrimSuffix(strings.TrimSpace(link), ".md")
+	if noteID, ok := r.byNoteID[link]; ok {
+		return noteID, true
+	}
+	noteID, ok := r.byID[link]
+	return noteID, ok
+}
+
+// Resolve the target of a Markdown (`[text](NoteID.md)`) or wiki (`[[NoteID]]`, `[[Title]]`) link
+func (r linkResolver) bodyLink(link string) (string, bool) {
+	if strings.HasPrefix(link, "[[") {
+		target := strings.TrimSuffix(strings.TrimPrefix(link, "[["), "]]")
+		if i := strings.IndexAny(target, "|#"); i >= 0 {
+			target = target[:i]
+		}
+		target = strings.TrimSuffix(strings.TrimSpace(target), ".md")
+		if noteID, ok := r.byNoteID[target]; ok {
+			return noteID, true
+		}
+		noteID, ok := r.byName[strings.ToLower(target)]
+		return noteID, ok
+	}
+
+	start := strings.LastIndex(link, "](")
+	if start < 0 {
+		return "", false
+	}
+	target := strings.TrimSuffix(link[start+2:], ")")
+	if i := strings.IndexAny(target, " #?"); i >= 0 {
+		target = target[:i]
+	}
+	if strings.Contains(target, "://") || !strings.HasSuffix(target, ".md") {
+		return "", false
+	}
+	noteID, ok := r.byNoteID[strings.TrimSuffix(path.Base(target), ".md")]
+	return noteID, ok
+}
+
+// Note IDs linked from the body of a note
+func (r linkResolver) bodyLinks(content string) []string {
+	var links []string
+	seen := make(map[string]bool)
+	forEachBodyLine(strings.Split(content, "\n"), func(_ int, line string) {
+		for _, link := range linkPattern.FindAllString(line, -1) {
+			if noteID, ok := r.bodyLink(link); ok && !seen[noteID] {
+				seen[noteID] = true
+				links = append(links, noteID)
+			}
+		}
+	})
+	return links
+}
+
+// Build the link graph of the notes from the front matter links in the index,
+// the links in the note bodies and the links recorded by auto-linking.
+// Links to notes outside the list are dropped.
+func BuildGraph(zettels []Zettel) Graph {
+	resolver := newLinkResolver(zettels)
+
+	nodes := make([]GraphNode, 0, len(zettels))
+	for _, z := range zettels {
+		tags := z.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		projects := ProjectNames(z.Tags)
+		if projects == nil {
+			projects = []string{}
+		}
+		nodes = append(nodes, GraphNode{NoteID: z.NoteID, ID: z.ID, Title: z.Title, Type: z.NoteType, Tags: tags, Projects: projects})
+	}
+
+	edges := []GraphEdge{}
+	for _, z := range zettels {
+		sources := make(map[string][]string)
+		typed := make(map[string]Link)
+		var targets []string
+		addSource := func(target, source string) {
+			if target == z.NoteID {
+				return
+			}
+			if _, seen := sources[target]; !seen {
+				targets = append(targets, target)
+			}
+			if !containsString(sources[target], source) {
+				sources[target] = append(sources[target], source)
+			}
+		}
+
+		for _, link := range z.Links {
+			if target, ok := resolver.frontMatterLink(link.To); ok {
+				if containsString(z.AutoLinks, link.To) {
+					addSource(target, EdgeAutoLink)
+				} else {
+					addSource(target, EdgeFrontMatter)
+				}
+				if !link.IsPlain() {
+					typed[target] = link
+				}
+			}
+		}
+		if content, err := os.ReadFile(z.NotePath); err == nil {
+			for _, target := range resolver.bodyLinks(string(content)) {
+				addSource(target, EdgeBody)
+			}
+		}
+
+		for _, target := range targets {
+			edges = append(edges, GraphEdge{From: z.NoteID, To: target, Sources: sources[target], Rel: typed[target].Rel, Note: typed[target].Note})
+		}
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return newGraph(nodes, edges)
+}
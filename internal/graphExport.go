@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats of `zk graph export`
+const (
+	GraphDOT     = "dot"
+	GraphGraphML = "graphml"
+	GraphJSON    = "json"
+	GraphMermaid = "mermaid"
+)
+
+var GraphFormats = []string{GraphDOT, GraphGraphML, GraphJSON, GraphMermaid}
+
+// Validate a graph export format
+func ValidateGraphFormat(format string) error {
+	for _, f := range GraphFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid graph format %q: must be one of %s", format, quoteList(GraphFormats))
+}
+
+// Write the graph in one of the export formats
+func WriteGraph(w io.Writer, format string, g Graph) error {
+	switch format {
+	case GraphDOT:
+		return writeDOT(w, g)
+	case GraphGraphML:
+		return writeGraphML(w, g)
+	case GraphJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(g)
+	case GraphMermaid:
+		return writeMermaid(w, g)
+	}
+	return ValidateGraphFormat(format)
+}
+
+// Quote a string as a Graphviz ID
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// Graphviz DOT: notes carry their type, tags and projects as attributes,
+// typed links are labelled with their relation, auto-links are dashed and
+// body-only links dotted
+func writeDOT(w io.Writer, g Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph zk {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, short_id=%s, type=%s, tags=%s, project=%s];\n",
+			dotQuote(node.NoteID), dotQuote(node.Title), dotQuote(node.ID), dotQuote(node.Type),
+			dotQuote(strings.Join(node.Tags, listSeparator)), dotQuote(strings.Join(node.Projects, listSeparator)))
+	}
+	for _, edge := range g.Edges {
+		attrs := "source=" + dotQuote(strings.Join(edge.Sources, ","))
+		if edge.Rel != "" {
+			attrs += ", rel=" + dotQuote(edge.Rel) + ", label=" + dotQuote(edge.Rel)
+		}
+		if edge.Note != "" {
+			attrs += ", note=" + dotQuote(edge.Note)
+		}
+		switch {
+		case containsString(edge.Sources, EdgeAutoLink):
+			attrs += ", style=dashed"
+		case !containsString(edge.Sources, EdgeFrontMatter):
+			attrs += ", style=dotted"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// GraphML (Gephi, yEd, Cytoscape)
+func writeGraphML(w io.Writer, g Graph) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for _, key := range []struct{ id, target, name string }{
+		{"label", "node", "label"},
+		{"short_id", "node", "short_id"},
+		{"type", "node", "type"},
+		{"tags", "node", "tags"},
+		{"project", "node", "project"},
+		{"source", "edge", "source"},
+		{"rel", "edge", "rel"},
+		{"note", "edge", "note"},
+	} {
+		fmt.Fprintf(&b, `  <key id="%s" for="%s" attr.name="%s" attr.type="string"/>`+"\n", key.id, key.target, key.name)
+	}
+	b.WriteString(`  <graph id="zk" edgedefault="directed">` + "\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, `    <node id="%s">`+"\n", xmlEscape(node.NoteID))
+		for _, data := range [][2]string{
+			{"label", node.Title},
+			{"short_id", node.ID},
+			{"type", node.Type},
+			{"tags", strings.Join(node.Tags, listSeparator)},
+			{"project", strings.Join(node.Projects, listSeparator)},
+		} {
+			fmt.Fprintf(&b, `      <data key="%s">%s</data>`+"\n", data[0], xmlEscape(data[1]))
+		}
+		b.WriteString("    </node>\n")
+	}
+	for i, edge := range g.Edges {
+		fmt.Fprintf(&b, `    <edge id="e%d" source="%s" target="%s">`+"\n", i, xmlEscape(edge.From), xmlEscape(edge.To))
+		fmt.Fprintf(&b, `      <data key="source">%s</data>`+"\n", xmlEscape(strings.Join(edge.Sources, ",")))
+		if edge.Rel != "" {
+			fmt.Fprintf(&b, `      <data key="rel">%s</data>`+"\n", xmlEscape(edge.Rel))
+		}
+		if edge.Note != "" {
+			fmt.Fprintf(&b, `      <data key="note">%s</data>`+"\n", xmlEscape(edge.Note))
+		}
+		b.WriteString("    </edge>\n")
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Mermaid node IDs may only contain letters, digits and underscores
+func mermaidID(noteID string) string {
+	return "n" + strings.Map(func(r rune) rune {
+		if r < 128 && (r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, noteID)
+}
+
+// Mermaid flowchart (renders in GitHub and many Markdown viewers)
+func writeMermaid(w io.Writer, g Graph) error {
+	label := strings.NewReplacer(`"`, "#quot;", "\n", " ")
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", mermaidID(node.NoteID), label.Replace(node.Title))
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if containsString(edge.Sources, EdgeAutoLink) {
+			arrow = "-.->"
+		}
+		if edge.Rel != "" {
+			arrow += "|" + label.Replace(edge.Rel) + "|"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", mermaidID(edge.From), arrow, mermaidID(edge.To))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
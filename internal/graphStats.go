@@ -0,0 +1,243 @@
+package internal
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	pageRankDamping           = 0.85
+	pageRankIterations        = 100
+	pageRankTolerance         = 1e-9
+	labelPropagationMaxRounds = 100
+)
+
+// Link counts of a note
+type NodeDegree struct {
+	Node GraphNode
+	In   int
+	Out  int
+}
+
+// Total number of links of the note, in either direction
+func (d NodeDegree) Total() int {
+	return d.In + d.Out
+}
+
+// In- and out-degree of every note, in graph order
+func (g Graph) Degrees() []NodeDegree {
+	degrees := make([]NodeDegree, len(g.Nodes))
+	for i, node := range g.Nodes {
+		degrees[i].Node = node
+	}
+	for _, edge := range g.Edges {
+		degrees[g.index[edge.From]].Out++
+		degrees[g.index[edge.To]].In++
+	}
+	return degrees
+}
+
+// Number of notes per total degree, ordered by degree
+func DegreeDistribution(degrees []NodeDegree) [][2]int {
+	counts := make(map[int]int)
+	for _, d := range degrees {
+		counts[d.Total()]++
+	}
+	distribution := make([][2]int, 0, len(counts))
+	for degree, count := range counts {
+		distribution = append(distribution, [2]int{degree, count})
+	}
+	sort.Slice(distribution, func(i, j int) bool { return distribution[i][0] < distribution[j][0] })
+	return distribution
+}
+
+// PageRank of every note, keyed by note ID. Notes without outgoing links
+// spread their rank evenly over all notes.
+func (g Graph) PageRank() map[string]float64 {
+	n := len(g.Nodes)
+	ranks := make(map[string]float64, n)
+	if n == 0 {
+		return ranks
+	}
+
+	outgoing := g.Outgoing()
+	for _, node := range g.Nodes {
+		ranks[node.NoteID] = 1 / float64(n)
+	}
+
+	for iteration := 0; iteration < pageRankIterations; iteration++ {
+		dangling := 0.0
+		for _, node := range g.Nodes {
+			if len(outgoing[node.NoteID]) == 0 {
+				dangling += ranks[node.NoteID]
+			}
+		}
+
+		base := (1-pageRankDamping)/float64(n) + pageRankDamping*dangling/float64(n)
+		next := make(map[string]float64, n)
+		for _, node := range g.Nodes {
+			next[node.NoteID] = base
+		}
+		for _, node := range g.Nodes {
+			edges := outgoing[node.NoteID]
+			for _, edge := range edges {
+				next[edge.To] += pageRankDamping * ranks[node.NoteID] / float64(len(edges))
+			}
+		}
+
+		delta := 0.0
+		for id, rank := range next {
+			delta += math.Abs(rank - ranks[id])
+		}
+		ranks = next
+		if delta < pageRankTolerance {
+			break
+		}
+	}
+	return ranks
+}
+
+// Weakly connected components (link direction ignored), largest first.
+// Each component lists its note IDs in graph order.
+func (g Graph) Components() [][]string {
+	neighbours := g.Neighbours()
+	seen := make(map[string]bool)
+	var components [][]string
+
+	for _, node := range g.Nodes {
+		if seen[node.NoteID] {
+			continue
+		}
+		seen[node.NoteID] = true
+		component := []string{}
+		queue := []string{node.NoteID}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			component = append(component, id)
+			for _, neighbour := range neighbours[id] {
+				if !seen[neighbour] {
+					seen[neighbour] = true
+					queue = append(queue, neighbour)
+				}
+			}
+		}
+		components = append(components, g.inGraphOrder(component))
+	}
+
+	sort.SliceStable(components, func(i, j int) bool { return len(components[i]) > len(components[j]) })
+	return components
+}
+
+// Communities detected by label propagation (link direction ignored), largest first.
+// Notes are visited in graph order and ties go to the label seen first, so the
+// result is deterministic.
+func (g Graph) Communities() [][]string {
+	neighbours := g.Neighbours()
+	labels := make(map[string]string, len(g.Nodes))
+	for _, node := range g.Nodes {
+		labels[node.NoteID] = node.NoteID
+	}
+
+	for round := 0; round < labelPropagationMaxRounds; round++ {
+		changed := false
+		for _, node := range g.Nodes {
+			if len(neighbours[node.NoteID]) == 0 {
+				continue
+			}
+			counts := make(map[string]int)
+			best, bestCount := labels[node.NoteID], 0
+			for _, neighbour := range neighbours[node.NoteID] {
+				label := labels[neighbour]
+				counts[label]++
+				if counts[label] > bestCount {
+					best, bestCount = label, counts[label]
+				}
+			}
+			// Keep the current label when it is as frequent as the best one
+			if counts[labels[node.NoteID]] == bestCount {
+				best = labels[node.NoteID]
+			}
+			if best != labels[node.NoteID] {
+				labels[node.NoteID] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	members := make(map[string][]string)
+	var order []string
+	for _, node := range g.Nodes {
+		label := labels[node.NoteID]
+		if _, exists := members[label]; !exists {
+			order = append(order, label)
+		}
+		members[label] = append(members[label], node.NoteID)
+	}
+	communities := make([][]string, 0, len(order))
+	for _, label := range order {
+		communities = append(communities, members[label])
+	}
+	sort.SliceStable(communities, func(i, j int) bool { return len(communities[i]) > len(communities[j]) })
+	return communities
+}
+
+// Sort note IDs in graph order
+func (g Graph) inGraphOrder(ids []string) []string {
+	sort.SliceStable(ids, func(i, j int) bool { return g.index[ids[i]] < g.index[ids[j]] })
+	return ids
+}
+
+// Shortest chain of links from one note to another, as the edges followed.
+// With `directed` false, links may be followed backwards (the returned edges
+// keep their original direction). Returns nil when there is no path.
+func (g Graph) ShortestPath(from, to string, directed bool) []GraphEdge {
+	if !g.HasNode(from) || !g.HasNode(to) || from == to {
+		return nil
+	}
+
+	steps := make(map[string][]GraphEdge)
+	for _, edge := range g.Edges {
+		steps[edge.From] = append(steps[edge.From], edge)
+		if !directed {
+			steps[edge.To] = append(steps[edge.To], edge)
+		}
+	}
+
+	via := map[string]GraphEdge{}
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, edge := range steps[id] {
+			next := edge.To
+			if next == id {
+				next = edge.From
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			via[next] = edge
+			if next == to {
+				var path []GraphEdge
+				for at := to; at != from; {
+					edge := via[at]
+					path = append([]GraphEdge{edge}, path...)
+					if edge.To == at {
+						at = edge.From
+					} else {
+						at = edge.To
+					}
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package internal
+
+import "fmt"
+
+// Which links to follow when walking the neighbourhood of a note
+const (
+	DirectionBoth = "both" // Links and backlinks
+	DirectionOut  = "out"  // Links from the note
+	DirectionIn   = "in"   // Backlinks to the note
+)
+
+var Directions = []string{DirectionBoth, DirectionOut, DirectionIn}
+
+// Validate a link direction
+func ValidateDirection(direction string) error {
+	for _, d := range Directions {
+		if direction == d {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid direction %q: must be one of %s", direction, quoteList(Directions))
+}
+
+// Line of a neighbourhood tree
+type TreeLine struct {
+	Prefix   string // Tree branches drawn before the note
+	Depth    int    // Number of links followed from the root
+	Incoming bool   // Reached through a backlink rather than a link
+	Node     GraphNode
+	Edge     GraphEdge // Link that led here (zero for the root)
+	Cycle    bool      // The note is an ancestor in this branch
+	Repeated bool      // The note was already expanded elsewhere in the tree
+}
+
+// Walk the links around a note up to `depth` levels, links before backlinks.
+// A note leading back to one of its ancestors is marked as a cycle and a note
+// already expanded elsewhere is not expanded again.
+func NeighbourTree(g Graph, root string, depth int, direction string) []TreeLine {
+	rootNode, ok := g.Node(root)
+	if !ok {
+		return nil
+	}
+
+	outgoing := g.Outgoing()
+	incoming := g.Incoming()
+	lines := []TreeLine{{Node: rootNode}}
+	expanded := map[string]bool{root: true}
+	ancestors := map[string]bool{root: true}
+
+	type step struct {
+		edge     GraphEdge
+		incoming bool
+	}
+	var walk func(noteID string, via GraphEdge, level int, indent string)
+	walk = func(noteID string, via GraphEdge, level int, indent string) {
+		var steps []step
+		if direction != DirectionIn {
+			for _, edge := range outgoing[noteID] {
+				steps = append(steps, step{edge, false})
+			}
+		}
+		if direction != DirectionOut {
+			for _, edge := range incoming[noteID] {
+				steps = append(steps, step{edge, true})
+			}
+		}
+
+		// The link just followed from the parent is not listed again
+		filtered := steps[:0]
+		for _, s := range steps {
+			if s.edge.From != via.From || s.edge.To != via.To {
+				filtered = append(filtered, s)
+			}
+		}
+		steps = filtered
+
+		for i, s := range steps {
+			branch, childIndent := "├── ", indent+"│   "
+			if i == len(steps)-1 {
+				branch, childIndent = "└── ", indent+"    "
+			}
+
+			next := s.edge.To
+			if s.incoming {
+				next = s.edge.From
+			}
+			node, _ := g.Node(next)
+			line := TreeLine{Prefix: indent + branch, Depth: level + 1, Incoming: s.incoming, Node: node, Edge: s.edge}
+			switch {
+			case ancestors[next]:
+				line.Cycle = true
+			case expanded[next]:
+				line.Repeated = true
+			}
+			lines = append(lines, line)
+
+			if line.Cycle || line.Repeated || level+1 >= depth {
+				continue
+			}
+			expanded[next] = true
+			ancestors[next] = true
+			walk(next, s.edge, level+1, childIndent)
+			delete(ancestors, next)
+		}
+	}
+	if depth > 0 {
+		walk(root, GraphEdge{}, 0, "")
+	}
+	return lines
+}
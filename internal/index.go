@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Groupings of an index note
+const (
+	IndexByType = "type"
+	IndexByTag  = "tag"
+)
+
+var IndexGroupings = []string{IndexByType, IndexByTag}
+
+// Group heading of notes without a more specific group
+const indexOtherGroup = "Other"
+
+// Managed section key of an index note: `index <source> (by <grouping>)`
+var indexKeyPattern = regexp.MustCompile(`^index (.+) \(by (\w+)\)$`)
+
+// Source and grouping of an index note
+type IndexSpec struct {
+	Source  string // Tag (`go`, also matching `go/...`) or query
+	IsTag   bool
+	GroupBy string
+}
+
+// Tags are single words; anything with query syntax is a query
+func NewIndexSpec(source, groupBy string) (IndexSpec, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return IndexSpec{}, fmt.Errorf("index source must not be empty")
+	}
+	if !containsString(IndexGroupings, groupBy) {
+		return IndexSpec{}, fmt.Errorf("invalid grouping %q: must be one of %s", groupBy, quoteList(IndexGroupings))
+	}
+	spec := IndexSpec{Source: source, GroupBy: groupBy}
+	if strings.HasPrefix(source, "tag:") && !strings.ContainsAny(source, " ()\"") {
+		spec.Source, spec.IsTag = strings.TrimPrefix(source, "tag:"), true
+	} else if !strings.ContainsAny(source, ": ()\"") {
+		spec.IsTag = true
+	}
+	if !spec.IsTag {
+		if _, err := ParseQuery(source); err != nil {
+			return IndexSpec{}, err
+		}
+	}
+	return spec, nil
+}
+
+// Parse the managed section key of an index note
+func ParseIndexKey(key string) (IndexSpec, bool) {
+	m := indexKeyPattern.FindStringSubmatch(key)
+	if m == nil {
+		return IndexSpec{}, false
+	}
+	spec, err := NewIndexSpec(m[1], m[2])
+	return spec, err == nil
+}
+
+// Managed section key of the index
+func (spec IndexSpec) Key() string {
+	source := spec.Source
+	if spec.IsTag {
+		source = "tag:" + source
+	}
+	return fmt.Sprintf("index %s (by %s)", source, spec.GroupBy)
+}
+
+// Active notes listed by the index
+func (spec IndexSpec) Match(zettels []Zettel, config Config) ([]Zettel, error) {
+	if !spec.IsTag {
+		return RunQuery(zettels, spec.Source, config)
+	}
+	matched := []Zettel{}
+	for _, z := range zettels {
+		if z.Deleted || z.Archived {
+			continue
+		}
+		if HasTag(z.Tags, spec.Source) {
+			matched = append(matched, z)
+		}
+	}
+	return matched, nil
+}
+
+// Groups a note is listed under
+func (spec IndexSpec) groups(z Zettel) []string {
+	if spec.GroupBy == IndexByType {
+		if z.NoteType == "" {
+			return []string{indexOtherGroup}
+		}
+		return []string{z.NoteType}
+	}
+
+	var groups []string
+	for _, tag := range z.Tags {
+		tag = strings.TrimSpace(tag)
+		if spec.IsTag {
+			// Group by sub-tag below the index tag
+			if subTag, ok := SubTag(tag, spec.Source); ok {
+				groups = append(groups, subTag)
+			}
+		} else if tag != "" {
+			groups = append(groups, tag)
+		}
+	}
+	if len(groups) == 0 {
+		return []string{indexOtherGroup}
+	}
+	return groups
+}
+
+// Format the notes as Markdown link lists under a heading per group.
+// Groups are sorted by name with "Other" last; notes are sorted by title.
+func (spec IndexSpec) Format(zettels []Zettel) string {
+	if len(zettels) == 0 {
+		return "_No matching notes._"
+	}
+
+	members := make(map[string][]Zettel)
+	for _, z := range zettels {
+		for _, group := range spec.groups(z) {
+			members[group] = append(members[group], z)
+		}
+	}
+	groups := make([]string, 0, len(members))
+	for group := range members {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i] == indexOtherGroup) != (groups[j] == indexOtherGroup) {
+			return groups[j] == indexOtherGroup
+		}
+		return strings.ToLower(groups[i]) < strings.ToLower(groups[j])
+	})
+
+	var b strings.Builder
+	for i, group := range groups {
+		notes := members[group]
+		sort.SliceStable(notes, func(i, j int) bool { return strings.ToLower(notes[i].Title) < strings.ToLower(notes[j].Title) })
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s", group, FormatLinkList(notes))
+	}
+	return b.String()
+}
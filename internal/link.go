@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Relations suggested for typed links (any other relation is accepted too)
+var LinkRelations = []string{"supports", "contradicts", "continues", "source-of", "example-of", "related"}
+
+// Link from a note to another, optionally typed with a relation and annotated
+// with a short note. Plain links are written as the bare note ID:
+//
+//	links:
+//	  - 20250301093000
+//	  - to: 20250302101500
+//	    rel: supports
+//	    note: Same result with a larger sample
+type Link struct {
+	To   string `yaml:"to" json:"to"`
+	Rel  string `yaml:"rel,omitempty" json:"rel,omitempty"`
+	Note string `yaml:"note,omitempty" json:"note,omitempty"`
+}
+
+// Links of a note
+type Links []Link
+
+// Fields of a link without its custom (un)marshalling
+type linkFields Link
+
+// Check whether the link carries only its target
+func (l Link) IsPlain() bool {
+	return l.Rel == "" && l.Note == ""
+}
+
+// `to` or `to (rel)`
+func (l Link) String() string {
+	if l.Rel == "" {
+		return l.To
+	}
+	return fmt.Sprintf("%s (%s)", l.To, l.Rel)
+}
+
+func (l Link) MarshalYAML() (interface{}, error) {
+	if l.IsPlain() {
+		return l.To, nil
+	}
+	return linkFields(l), nil
+}
+
+func (l *Link) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = Link{To: strings.TrimSpace(value.Value)}
+		return nil
+	}
+	var fields linkFields
+	if err := value.Decode(&fields); err != nil {
+		return err
+	}
+	*l = Link(fields)
+	l.To = strings.TrimSpace(l.To)
+	return nil
+}
+
+func (l Link) MarshalJSON() ([]byte, error) {
+	if l.IsPlain() {
+		return json.Marshal(l.To)
+	}
+	return json.Marshal(linkFields(l))
+}
+
+func (l *Link) UnmarshalJSON(data []byte) error {
+	var to string
+	if err := json.Unmarshal(data, &to); err == nil {
+		*l = Link{To: strings.TrimSpace(to)}
+		return nil
+	}
+	var fields linkFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*l = Link(fields)
+	l.To = strings.TrimSpace(l.To)
+	return nil
+}
+
+// Plain links to the given note IDs
+func PlainLinks(noteIDs ...string) Links {
+	links := make(Links, 0, len(noteIDs))
+	for _, noteID := range noteIDs {
+		links = append(links, Link{To: noteID})
+	}
+	return links
+}
+
+// Note IDs the links point to
+func (links Links) Targets() []string {
+	targets := make([]string, 0, len(links))
+	for _, link := range links {
+		targets = append(targets, link.To)
+	}
+	return targets
+}
+
+// Find the link to a note
+func (links Links) Find(to string) (Link, bool) {
+	for _, link := range links {
+		if link.To == to {
+			return link, true
+		}
+	}
+	return Link{}, false
+}
+
+// Check whether there is a link to a note
+func (links Links) Has(to string) bool {
+	_, ok := links.Find(to)
+	return ok
+}
+
+// Add links, skipping duplicates. The relation and note of an existing link
+// are replaced when the added link carries them.
+func MergeLinks(existing Links, added ...Link) Links {
+	merged := append(Links{}, existing...)
+	for _, link := range added {
+		found := false
+		for i := range merged {
+			if merged[i].To != link.To {
+				continue
+			}
+			found = true
+			if link.Rel != "" {
+				merged[i].Rel = link.Rel
+			}
+			if link.Note != "" {
+				merged[i].Note = link.Note
+			}
+			break
+		}
+		if !found {
+			merged = append(merged, link)
+		}
+	}
+	return merged
+}
+
+// Normalize a relation name: lower case with words joined by hyphens
+func NormalizeRelation(rel string) string {
+	return strings.Join(strings.Fields(strings.ToLower(rel)), "-")
+}
+
+// Link from another note pointing at a note
+type Backlink struct {
+	Source Zettel
+	Link   Link
+}
+
+// Active notes whose front matter links to the target, in index order
+func Backlinks(target Zettel, zettels []Zettel) []Backlink {
+	var backlinks []Backlink
+	for _, z := range zettels {
+		if z.NoteID == target.NoteID || z.Deleted || z.Archived {
+			continue
+		}
+		for _, link := range z.Links {
+			// Links written by `zk link --auto` use the short ID
+			if link.To == target.NoteID || link.To == target.ID {
+				backlinks = append(backlinks, Backlink{Source: z, Link: link})
+				break
+			}
+		}
+	}
+	return backlinks
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var managedBeginPattern = regexp.MustCompile(`<!-- zk:begin (.+?) -->`)
+
+// Markers delimiting a section of a note that zk regenerates
+func managedMarkers(key string) (string, string) {
+	return fmt.Sprintf("<!-- zk:begin %s -->", key), fmt.Sprintf("<!-- zk:end %s -->", key)
+}
+
+// Replace the managed section of a body (appended when missing), leaving the rest untouched
+func ReplaceManagedSection(body, key, content string) string {
+	begin, end := managedMarkers(key)
+	section := fmt.Sprintf("%s\n%s\n%s", begin, strings.TrimRight(content, "\n"), end)
+
+	start := strings.Index(body, begin)
+	if start >= 0 {
+		if stop := strings.Index(body[start:], end); stop >= 0 {
+			return body[:start] + section + body[start+stop+len(end):]
+		}
+	}
+
+	return strings.TrimRight(body, "\n") + "\n\n" + section + "\n"
+}
+
+// Keys of the managed sections of a body, in order
+func ManagedSectionKeys(body string) []string {
+	var keys []string
+	for _, m := range managedBeginPattern.FindAllStringSubmatch(body, -1) {
+		keys = append(keys, m[1])
+	}
+	return keys
+}
+
+// Format notes as a Markdown link list
+func FormatLinkList(zettels []Zettel) string {
+	if len(zettels) == 0 {
+		return "_No matching notes._"
+	}
+	var b strings.Builder
+	for _, zettel := range zettels {
+		fmt.Fprintf(&b, "- [%s](%s.md)\n", zettel.Title, zettel.NoteID)
+	}
+	return b.String()
+}
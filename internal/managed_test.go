@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplaceManagedSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		key     string
+		content string
+		want    string
+	}{
+		{
+			"appended",
+			"Intro\n\n",
+			"backlinks",
+			"- a\n",
+			"Intro\n\n<!-- zk:begin backlinks -->\n- a\n<!-- zk:end backlinks -->\n",
+		},
+		{
+			"empty body",
+			"",
+			"q",
+			"x",
+			"\n\n<!-- zk:begin q -->\nx\n<!-- zk:end q -->\n",
+		},
+		{
+			"replaced in place",
+			"A\n<!-- zk:begin q -->\nold\nlines\n<!-- zk:end q -->\nB\n",
+			"q",
+			"new\n\n",
+			"A\n<!-- zk:begin q -->\nnew\n<!-- zk:end q -->\nB\n",
+		},
+		{
+			"other sections untouched",
+			"<!-- zk:begin a -->\n1\n<!-- zk:end a -->\n<!-- zk:begin b -->\n2\n<!-- zk:end b -->\n",
+			"b",
+			"3",
+			"<!-- zk:begin a -->\n1\n<!-- zk:end a -->\n<!-- zk:begin b -->\n3\n<!-- zk:end b -->\n",
+		},
+		{
+			"key with spaces",
+			"A\n<!-- zk:begin tag:go type:permanent -->\nold\n<!-- zk:end tag:go type:permanent -->\n",
+			"tag:go type:permanent",
+			"new",
+			"A\n<!-- zk:begin tag:go type:permanent -->\nnew\n<!-- zk:end tag:go type:permanent -->\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReplaceManagedSection(tt.body, tt.key, tt.content)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			// Regenerating with the same content changes nothing
+			if again := ReplaceManagedSection(got, tt.key, tt.content); again != got {
+				t.Errorf("second run gave %q", again)
+			}
+		})
+	}
+}
+
+func TestManagedSectionKeys(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"No sections\n", nil},
+		{"<!-- zk:begin backlinks -->\n<!-- zk:end backlinks -->\n", []string{"backlinks"}},
+		{"<!-- zk:begin tag:go -->\n\n<!-- zk:end tag:go -->\ntext\n<!-- zk:begin a b -->\n<!-- zk:end a b -->", []string{"tag:go", "a b"}},
+	}
+	for _, tt := range tests {
+		if got := ManagedSectionKeys(tt.body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ManagedSectionKeys(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLinkList(t *testing.T) {
+	tests := []struct {
+		zettels []Zettel
+		want    string
+	}{
+		{nil, "_No matching notes._"},
+		{
+			[]Zettel{{Title: "Go", NoteID: "20250301090000"}, {Title: "Rust", NoteID: "20250302090000"}},
+			"- [Go](20250301090000.md)\n- [Rust](20250302090000.md)\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := FormatLinkList(tt.zettels); got != tt.want {
+			t.Errorf("FormatLinkList = %q, want %q", got, tt.want)
+		}
+	}
+}
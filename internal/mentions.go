@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Markdown links (`[text](target)`) and wiki links (`[[target]]`)
+var linkPattern = regexp.MustCompile(`\[\[[^\]]*\]\]|\[[^\]]*\]\([^)]*\)`)
+
+// Plain-text occurrence of a note title or alias
+type Mention struct {
+	Line int       // 1-based line number in the note file
+	Text string    // Whole line
+	Term string    // Title or alias as written in the line
+	Span MatchSpan // Byte offsets of the term in Text
+}
+
+// Names a note goes by: its title followed by its aliases
+func NoteNames(z Zettel) []string {
+	names := []string{}
+	for _, name := range append([]string{z.Title}, z.Aliases...) {
+		if name = strings.TrimSpace(name); name != "" && !containsFold(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check that a match is not part of a longer word (only for terms starting or ending with a letter or digit)
+func onWordBoundary(text string, start, end int) bool {
+	isWordRune := func(r rune) bool {
+		return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+	}
+	first, _ := utf8.DecodeRuneInString(text[start:end])
+	last, _ := utf8.DecodeLastRuneInString(text[start:end])
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(first) && isWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(last) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+// Find plain-text occurrences of any of the terms in note content.
+// Front matter, code blocks and existing links are skipped.
+func FindMentions(content string, terms []string) []Mention {
+	var quoted []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); utf8.RuneCountInString(term) >= 2 {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	// Longer terms first so that "Kubernetes basics" wins over "Kubernetes"
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	pattern := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+
+	var mentions []Mention
+	lines := strings.Split(content, "\n")
+	forEachBodyLine(lines, func(i int, line string) {
+		line = strings.TrimRight(line, "\r")
+		links := linkPattern.FindAllStringIndex(line, -1)
+
+		for _, loc := range pattern.FindAllStringIndex(line, -1) {
+			if !onWordBoundary(line, loc[0], loc[1]) {
+				continue
+			}
+			insideLink := false
+			for _, link := range links {
+				if loc[0] < link[1] && loc[1] > link[0] {
+					insideLink = true
+					break
+				}
+			}
+			if insideLink {
+				continue
+			}
+			mentions = append(mentions, Mention{
+				Line: i + 1,
+				Text: line,
+				Term: line[loc[0]:loc[1]],
+				Span: MatchSpan{Start: loc[0], End: loc[1]},
+			})
+		}
+	})
+	return mentions
+}
+
+// Replace mentions with Markdown links to the note
+func LinkMentions(content string, mentions []Mention, title, noteID string) (string, error) {
+	lines := strings.Split(content, "\n")
+	for _, mention := range mentions {
+		if mention.Line < 1 || mention.Line > len(lines) {
+			return content, fmt.Errorf("❌ Line %d is out of range", mention.Line)
+		}
+		line := strings.TrimRight(lines[mention.Line-1], "\r")
+		if line != mention.Text || line[mention.Span.Start:mention.Span.End] != mention.Term {
+			return content, fmt.Errorf("❌ Line %d has changed since it was scanned", mention.Line)
+		}
+	}
+
+	// Replace from the end so that earlier offsets stay valid
+	sorted := append([]Mention(nil), mentions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Line != sorted[j].Line {
+			return sorted[i].Line > sorted[j].Line
+		}
+		return sorted[i].Span.Start > sorted[j].Span.Start
+	})
+
+	link := fmt.Sprintf("[%s](%s.md)", title, noteID)
+	for _, mention := range sorted {
+		line := lines[mention.Line-1]
+		lines[mention.Line-1] = line[:mention.Span.Start] + link + line[mention.Span.End:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Mentions of a note found in another note
+type UnlinkedMentions struct {
+	Source   Zettel
+	Mentions []Mention
+}
+
+// Find active notes mentioning the target's title or aliases in plain text
+// without linking to it, ordered by note ID
+func FindUnlinkedMentions(target Zettel, zettels []Zettel) []UnlinkedMentions {
+	terms := NoteNames(target)
+
+	var results []UnlinkedMentions
+	for _, z := range zettels {
+		if z.NoteID == target.NoteID || z.Deleted || z.Archived || z.Links.Has(target.NoteID) {
+			continue
+		}
+		content, err := os.ReadFile(z.NotePath)
+		if err != nil {
+			continue
+		}
+		if mentions := FindMentions(string(content), terms); len(mentions) > 0 {
+			results = append(results, UnlinkedMentions{Source: z, Mentions: mentions})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Source.NoteID < results[j].Source.NoteID })
+	return results
+}
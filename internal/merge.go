@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"path"
+	"strings"
+)
+
+// Rewrite every Markdown and wiki link in the body of note content,
+// skipping the front matter and code blocks
+func rewriteBodyLinks(content string, rewrite func(link string) string) (string, []LineChange) {
+	return rewriteLinks(content, forEachBodyLine, rewrite)
+}
+
+// Rewrite every Markdown and wiki link of the lines visited by a line walker
+func rewriteLinks(content string, walk func(lines []string, fn func(i int, line string)), rewrite func(link string) string) (string, []LineChange) {
+	var changes []LineChange
+	lines := strings.Split(content, "\n")
+	walk(lines, func(i int, line string) {
+		updated := linkPattern.ReplaceAllStringFunc(line, rewrite)
+		if updated != line {
+			changes = append(changes, LineChange{Line: i + 1, Before: line, After: updated})
+			lines[i] = updated
+		}
+	})
+	return strings.Join(lines, "\n"), changes
+}
+
+// Split a wiki link into its target, anchor and label (`[[target#anchor|label]]`)
+func splitWikiLink(link string) (target, anchor, label string, hasAnchor, hasLabel bool) {
+	inner := strings.TrimSuffix(strings.TrimPrefix(link, "[["), "]]")
+	dest, label, hasLabel := strings.Cut(inner, "|")
+	target, anchor, hasAnchor = strings.Cut(dest, "#")
+	return target, anchor, label, hasAnchor, hasLabel
+}
+
+func joinWikiLink(target, anchor, label string, hasAnchor, hasLabel bool) string {
+	if hasAnchor {
+		target += "#" + anchor
+	}
+	if hasLabel {
+		target += "|" + label
+	}
+	return "[[" + target + "]]"
+}
+
+// Split a Markdown link into its text, destination file and the rest of the
+// destination (`[text](dir/NoteID.md#anchor)` → `text`, `dir/NoteID.md`, `#anchor`)
+func splitMarkdownLink(link string) (text, dest, rest string, ok bool) {
+	start := strings.LastIndex(link, "](")
+	if start < 0 {
+		return "", "", "", false
+	}
+	text, dest = link[1:start], strings.TrimSuffix(link[start+2:], ")")
+	if i := strings.IndexAny(dest, " #?"); i >= 0 {
+		dest, rest = dest[:i], dest[i:]
+	}
+	return text, dest, rest, true
+}
+
+// Check whether a wiki link target names a note (by note ID or title)
+func wikiTargets(target string, z Zettel) bool {
+	target = strings.TrimSuffix(strings.TrimSpace(target), ".md")
+	return target == z.NoteID || strings.EqualFold(target, z.Title)
+}
+
+// Point a link at the note `to` instead of `from`. Texts naming the old note
+// by its title are renamed too.
+func redirectLink(link string, from, to Zettel) string {
+	if strings.HasPrefix(link, "[[") {
+		target, anchor, label, hasAnchor, hasLabel := splitWikiLink(link)
+		if !wikiTargets(target, from) {
+			return link
+		}
+		if strings.TrimSuffix(strings.TrimSpace(target), ".md") == from.NoteID {
+			target = to.NoteID
+		} else {
+			target = to.Title
+		}
+		if hasLabel && strings.EqualFold(strings.TrimSpace(label), from.Title) {
+			label = to.Title
+		}
+		return joinWikiLink(target, anchor, label, hasAnchor, hasLabel)
+	}
+
+	text, dest, rest, ok := splitMarkdownLink(link)
+	if !ok || path.Base(dest) != from.NoteID+".md" {
+		return link
+	}
+	if strings.EqualFold(strings.TrimSpace(text), from.Title) {
+		text = to.Title
+	}
+	dest = strings.TrimSuffix(dest, from.NoteID+".md") + to.NoteID + ".md"
+	return "[" + text + "](" + dest + rest + ")"
+}
+
+// Point the body links to a note at another note: `[B](B.md)` becomes
+// `[A](A.md)` and `[[B]]` becomes `[[A]]`. Links with other texts keep them.
+func RedirectLinks(content string, from, to Zettel) (string, []LineChange) {
+	return rewriteBodyLinks(content, func(link string) string {
+		return redirectLink(link, from, to)
+	})
+}
+
+// Point the front matter links to a note at another note. Links ending up
+// on the note carrying them (`self`) are dropped, and duplicates are merged.
+func RedirectFrontMatterLinks(links Links, from, to, self Zettel) (Links, bool) {
+	changed := false
+	redirected := Links{}
+	for _, link := range links {
+		if link.To == from.NoteID || link.To == from.ID {
+			link.To = to.NoteID
+			changed = true
+		}
+		if link.To == self.NoteID || link.To == self.ID {
+			changed = true
+			continue
+		}
+		redirected = MergeLinks(redirected, link)
+	}
+	return redirected, changed
+}
+
+// Union of two tag lists, keeping the first spelling of each tag
+func MergeTags(tags []string, added ...string) []string {
+	merged := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range append(append([]string{}, tags...), added...) {
+		if key := normalizeTag(tag); key != "" && !seen[key] {
+			seen[key] = true
+			merged = append(merged, strings.TrimSpace(tag))
+		}
+	}
+	return merged
+}
+
+// Add names to a note's aliases, skipping its title and names already there
+func MergeAliases(aliases []string, title string, added ...string) []string {
+	merged := append([]string{}, aliases...)
+	for _, name := range added {
+		if name = strings.TrimSpace(name); name != "" && !strings.EqualFold(name, title) && !containsFold(merged, name) {
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
+
+// Replace the links to a note in a body (without front matter) with their
+// text, for links that would point at the note containing them
+func UnlinkNote(body string, z Zettel) string {
+	unlinked, _ := rewriteLinks(body, forEachTextLine, func(link string) string {
+		if strings.HasPrefix(link, "[[") {
+			target, _, label, _, hasLabel := splitWikiLink(link)
+			if !wikiTargets(target, z) {
+				return link
+			}
+			if hasLabel {
+				return label
+			}
+			return z.Title
+		}
+		text, dest, _, ok := splitMarkdownLink(link)
+		if !ok || path.Base(dest) != z.NoteID+".md" {
+			return link
+		}
+		return text
+	})
+	return unlinked
+}
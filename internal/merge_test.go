@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	alphaNote = Zettel{ID: "1", NoteID: "20250301090000", Title: "Alpha"}
+	betaNote  = Zettel{ID: "2", NoteID: "20250302090000", Title: "Beta"}
+	gammaNote = Zettel{ID: "3", NoteID: "20250303090000", Title: "Gamma"}
+)
+
+func TestRedirectLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		changes int
+	}{
+		{
+			"markdown links",
+			"[Beta](20250302090000.md) and [see](../notes/20250302090000.md#x)",
+			"[Alpha](20250301090000.md) and [see](../notes/20250301090000.md#x)",
+			1,
+		},
+		{
+			"wiki links",
+			"[[Beta]] [[20250302090000|Beta]] [[beta#h]] [[20250302090000.md|label]]",
+			"[[Alpha]] [[20250301090000|Alpha]] [[Alpha#h]] [[20250301090000|label]]",
+			1,
+		},
+		{
+			"other notes",
+			"[Gamma](20250303090000.md) [[Gamma]] [Beta](https://example.com)",
+			"[Gamma](20250303090000.md) [[Gamma]] [Beta](https://example.com)",
+			0,
+		},
+		{
+			"front matter and code blocks",
+			"---\nlinks: [20250302090000]\n---\n```\n[[Beta]]\n```\n[[Beta]]",
+			"---\nlinks: [20250302090000]\n---\n```\n[[Beta]]\n```\n[[Alpha]]",
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changes := RedirectLinks(tt.content, betaNote, alphaNote)
+			if got != tt.want || len(changes) != tt.changes {
+				t.Errorf("got %q with %d changes, want %q with %d", got, len(changes), tt.want, tt.changes)
+			}
+		})
+	}
+}
+
+func TestRedirectFrontMatterLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		links   Links
+		self    Zettel
+		want    Links
+		changed bool
+	}{
+		{"untouched", PlainLinks(gammaNote.NoteID), betaNote, PlainLinks(gammaNote.NoteID), false},
+		{"redirected", Links{{To: betaNote.NoteID, Rel: "supports"}}, gammaNote, Links{{To: alphaNote.NoteID, Rel: "supports"}}, true},
+		{"by ID", PlainLinks(betaNote.ID), gammaNote, PlainLinks(alphaNote.NoteID), true},
+		{"merged duplicates", Links{{To: betaNote.NoteID, Rel: "supports"}, {To: alphaNote.NoteID}}, gammaNote, Links{{To: alphaNote.NoteID, Rel: "supports"}}, true},
+		{"self links dropped", PlainLinks(betaNote.NoteID, alphaNote.NoteID, gammaNote.NoteID), alphaNote, PlainLinks(gammaNote.NoteID), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := RedirectFrontMatterLinks(tt.links, betaNote, alphaNote, tt.self)
+			if !reflect.DeepEqual(got, tt.want) || changed != tt.changed {
+				t.Errorf("got %+v, %v; want %+v, %v", got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
+
+func TestMergeTagsAndAliases(t *testing.T) {
+	tags := MergeTags([]string{"go", "Rust"}, "rust", " ", " lang ")
+	if want := []string{"go", "Rust", "lang"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("MergeTags = %q, want %q", tags, want)
+	}
+
+	aliases := MergeAliases([]string{"B"}, "Alpha", "b", "alpha", " Beta ", "")
+	if want := []string{"B", "Beta"}; !reflect.DeepEqual(aliases, want) {
+		t.Errorf("MergeAliases = %q, want %q", aliases, want)
+	}
+}
+
+func TestUnlinkNote(t *testing.T) {
+	body := strings.Join([]string{
+		"---",
+		"See [Alpha](20250301090000.md), [[Alpha]], [[20250301090000|the note]] and [[Beta]].",
+		"```",
+		"[[Alpha]]",
+		"```",
+	}, "\n")
+	want := strings.Join([]string{
+		"---",
+		"See Alpha, Alpha, the note and [[Beta]].",
+		"```",
+		"[[Alpha]]",
+		"```",
+	}, "\n")
+	if got := UnlinkNote(body, alphaNote); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
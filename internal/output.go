@@ -0,0 +1,446 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of read commands
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+	OutputTSV   = "tsv"
+	OutputIDs   = "ids"
+)
+
+var OutputFormats = []string{OutputTable, OutputJSON, OutputYAML, OutputCSV, OutputTSV, OutputIDs}
+
+// Separator of list values (tags, links) inside a CSV/TSV cell
+const listSeparator = ";"
+
+// Validate an `--output` format
+func ValidateOutputFormat(format string) error {
+	for _, f := range OutputFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %q: must be one of %s", format, quoteList(OutputFormats))
+}
+
+// Check whether the output is meant for scripts rather than humans
+func IsMachineOutput(format string) bool {
+	return format != "" && format != OutputTable
+}
+
+// Record that can be written as a row of CSV/TSV output
+type OutputRecord interface {
+	Header() []string
+	Row() []string
+	Key() string // Printed by `--output ids`
+}
+
+// Stable output schema of a note
+type NoteRecord struct {
+	ID          string       `json:"id" yaml:"id"`
+	NoteID      string       `json:"note_id" yaml:"note_id"`
+	Title       string       `json:"title" yaml:"title"`
+	Type        string       `json:"type" yaml:"type"`
+	Tags        []string     `json:"tags" yaml:"tags"`
+	Aliases     []string     `json:"aliases" yaml:"aliases"`
+	Links       []LinkRecord `json:"links" yaml:"links"`
+	TaskStatus  string       `json:"task_status" yaml:"task_status"`
+	Priority    string       `json:"priority" yaml:"priority"`
+	Due         string       `json:"due" yaml:"due"`
+	CompletedAt string       `json:"completed_at" yaml:"completed_at"`
+	Recur       string       `json:"recur" yaml:"recur"`
+	CreatedAt   string       `json:"created_at" yaml:"created_at"`
+	UpdatedAt   string       `json:"updated_at" yaml:"updated_at"`
+	Path        string       `json:"path" yaml:"path"`
+	Archived    bool         `json:"archived" yaml:"archived"`
+	Deleted     bool         `json:"deleted" yaml:"deleted"`
+	Sequence    string       `json:"sequence" yaml:"sequence"`
+}
+
+// Link of a note record, with the same fields whether it is typed or not
+type LinkRecord struct {
+	To   string `json:"to" yaml:"to"`
+	Rel  string `json:"rel" yaml:"rel"`
+	Note string `json:"note" yaml:"note"`
+}
+
+// Links of a CSV/TSV cell: `to` or `to (rel)`
+func joinLinks(links []LinkRecord) string {
+	cells := make([]string, len(links))
+	for i, link := range links {
+		cells[i] = Link(link).String()
+	}
+	return strings.Join(cells, listSeparator)
+}
+
+func NewNoteRecord(z Zettel) NoteRecord {
+	tags := z.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	aliases := z.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	links := make([]LinkRecord, 0, len(z.Links))
+	for _, link := range z.Links {
+		links = append(links, LinkRecord(link))
+	}
+	return NoteRecord{
+		ID:          z.ID,
+		NoteID:      z.NoteID,
+		Title:       z.Title,
+		Type:        z.NoteType,
+		Tags:        tags,
+		Aliases:     aliases,
+		Links:       links,
+		TaskStatus:  z.TaskStatus,
+		Priority:    z.Priority,
+		Due:         z.Due,
+		CompletedAt: z.CompletedAt,
+		Recur:       z.Recur,
+		CreatedAt:   z.CreatedAt,
+		UpdatedAt:   z.UpdatedAt,
+		Path:        z.NotePath,
+		Archived:    z.Archived,
+		Deleted:     z.Deleted,
+		Sequence:    z.Sequence,
+	}
+}
+
+func NewNoteRecords(zettels []Zettel) []NoteRecord {
+	records := make([]NoteRecord, 0, len(zettels))
+	for _, z := range zettels {
+		records = append(records, NewNoteRecord(z))
+	}
+	return records
+}
+
+func (r NoteRecord) Header() []string {
+	return []string{"id", "note_id", "title", "type", "tags", "links", "task_status", "priority", "due", "completed_at", "recur", "created_at", "updated_at", "path", "archived", "deleted", "aliases", "sequence"}
+}
+
+func (r NoteRecord) Row() []string {
+	return []string{
+		r.ID, r.NoteID, r.Title, r.Type,
+		strings.Join(r.Tags, listSeparator), joinLinks(r.Links),
+		r.TaskStatus, r.Priority, r.Due, r.CompletedAt, r.Recur,
+		r.CreatedAt, r.UpdatedAt, r.Path,
+		strconv.FormatBool(r.Archived), strconv.FormatBool(r.Deleted),
+		strings.Join(r.Aliases, listSeparator), r.Sequence,
+	}
+}
+
+func (r NoteRecord) Key() string {
+	return r.ID
+}
+
+// Stable output schema of `zk show`
+type ShowRecord struct {
+	NoteRecord     `yaml:",inline"`
+	ChecklistDone  int    `json:"checklist_done" yaml:"checklist_done"`
+	ChecklistTotal int    `json:"checklist_total" yaml:"checklist_total"`
+	Body           string `json:"body" yaml:"body"`
+}
+
+func (r ShowRecord) Header() []string {
+	return append(r.NoteRecord.Header(), "checklist_done", "checklist_total", "body")
+}
+
+func (r ShowRecord) Row() []string {
+	return append(r.NoteRecord.Row(), strconv.Itoa(r.ChecklistDone), strconv.Itoa(r.ChecklistTotal), r.Body)
+}
+
+// Stable output schema of a `zk search` result
+type SearchRecord struct {
+	NoteRecord `yaml:",inline"`
+	Score      float64  `json:"score" yaml:"score"`
+	Matches    []string `json:"matches" yaml:"matches"`
+}
+
+func (r SearchRecord) Header() []string {
+	return append(r.NoteRecord.Header(), "score", "matches")
+}
+
+func (r SearchRecord) Row() []string {
+	return append(r.NoteRecord.Row(), strconv.FormatFloat(r.Score, 'f', 4, 64), strings.Join(r.Matches, listSeparator))
+}
+
+// Stable output schema of a checkbox listed by `zk task list --inline`
+type CheckboxRecord struct {
+	Ref     string `json:"ref" yaml:"ref"`
+	ID      string `json:"id" yaml:"id"`
+	NoteID  string `json:"note_id" yaml:"note_id"`
+	Title   string `json:"title" yaml:"title"`
+	Line    int    `json:"line" yaml:"line"`
+	Text    string `json:"text" yaml:"text"`
+	Checked bool   `json:"checked" yaml:"checked"`
+}
+
+func (r CheckboxRecord) Header() []string {
+	return []string{"ref", "id", "note_id", "title", "line", "text", "checked"}
+}
+
+func (r CheckboxRecord) Row() []string {
+	return []string{r.Ref, r.ID, r.NoteID, r.Title, strconv.Itoa(r.Line), r.Text, strconv.FormatBool(r.Checked)}
+}
+
+func (r CheckboxRecord) Key() string {
+	return r.Ref
+}
+
+// Stable output schema of an unlinked mention listed by `zk mentions`
+type MentionRecord struct {
+	Ref    string `json:"ref" yaml:"ref"`
+	ID     string `json:"id" yaml:"id"`
+	NoteID string `json:"note_id" yaml:"note_id"`
+	Title  string `json:"title" yaml:"title"`
+	Line   int    `json:"line" yaml:"line"`
+	Term   string `json:"term" yaml:"term"`
+	Text   string `json:"text" yaml:"text"`
+}
+
+func (r MentionRecord) Header() []string {
+	return []string{"ref", "id", "note_id", "title", "line", "term", "text"}
+}
+
+func (r MentionRecord) Row() []string {
+	return []string{r.Ref, r.ID, r.NoteID, r.Title, strconv.Itoa(r.Line), r.Term, r.Text}
+}
+
+func (r MentionRecord) Key() string {
+	return r.Ref
+}
+
+// Stable output schema of a tag listed by `zk tags`
+type TagRecord struct {
+	Tag      string `json:"tag" yaml:"tag"`
+	Count    int    `json:"count" yaml:"count"`
+	LastUsed string `json:"last_used" yaml:"last_used"`
+}
+
+func (r TagRecord) Header() []string {
+	return []string{"tag", "count", "last_used"}
+}
+
+func (r TagRecord) Row() []string {
+	return []string{r.Tag, strconv.Itoa(r.Count), r.LastUsed}
+}
+
+func (r TagRecord) Key() string {
+	return r.Tag
+}
+
+// Stable output schema of a project listed by `zk project list` and `zk project show`
+type ProjectRecord struct {
+	Name     string `json:"name" yaml:"name"`
+	Notes    int    `json:"notes" yaml:"notes"`
+	Tasks    int    `json:"tasks" yaml:"tasks"`
+	Done     int    `json:"done" yaml:"done"`
+	Overdue  int    `json:"overdue" yaml:"overdue"`
+	Progress int    `json:"progress" yaml:"progress"` // Percentage of done tasks
+
+	ChecklistDone  int `json:"checklist_done" yaml:"checklist_done"`
+	ChecklistTotal int `json:"checklist_total" yaml:"checklist_total"`
+}
+
+func NewProjectRecord(summary ProjectSummary) ProjectRecord {
+	return ProjectRecord{
+		Name:     summary.Name,
+		Notes:    len(summary.Notes),
+		Tasks:    len(summary.Tasks),
+		Done:     summary.Done,
+		Overdue:  len(summary.Overdue),
+		Progress: summary.Progress(),
+
+		ChecklistDone:  summary.ChecklistDone,
+		ChecklistTotal: summary.ChecklistTotal,
+	}
+}
+
+func (r ProjectRecord) Header() []string {
+	return []string{"name", "notes", "tasks", "done", "overdue", "progress", "checklist_done", "checklist_total"}
+}
+
+func (r ProjectRecord) Row() []string {
+	return []string{r.Name, strconv.Itoa(r.Notes), strconv.Itoa(r.Tasks), strconv.Itoa(r.Done), strconv.Itoa(r.Overdue), strconv.Itoa(r.Progress),
+		strconv.Itoa(r.ChecklistDone), strconv.Itoa(r.ChecklistTotal)}
+}
+
+func (r ProjectRecord) Key() string {
+	return r.Name
+}
+
+// Stable output schema of a saved query listed by `zk query list`
+type SavedQueryRecord struct {
+	Name   string `json:"name" yaml:"name"`
+	Query  string `json:"query" yaml:"query"`
+	NoteID string `json:"note_id" yaml:"note_id"`
+}
+
+func (r SavedQueryRecord) Header() []string {
+	return []string{"name", "query", "note_id"}
+}
+
+func (r SavedQueryRecord) Row() []string {
+	return []string{r.Name, r.Query, r.NoteID}
+}
+
+func (r SavedQueryRecord) Key() string {
+	return r.Name
+}
+
+// Stable output schema of a note of `zk graph stats`
+type GraphStatRecord struct {
+	ID        string  `json:"id" yaml:"id"`
+	NoteID    string  `json:"note_id" yaml:"note_id"`
+	Title     string  `json:"title" yaml:"title"`
+	In        int     `json:"in" yaml:"in"`
+	Out       int     `json:"out" yaml:"out"`
+	PageRank  float64 `json:"pagerank" yaml:"pagerank"`
+	Component int     `json:"component" yaml:"component"` // 1-based, largest first
+	Community int     `json:"community" yaml:"community"` // 1-based, largest first
+}
+
+func (r GraphStatRecord) Header() []string {
+	return []string{"id", "note_id", "title", "in", "out", "pagerank", "component", "community"}
+}
+
+func (r GraphStatRecord) Row() []string {
+	return []string{
+		r.ID, r.NoteID, r.Title, strconv.Itoa(r.In), strconv.Itoa(r.Out),
+		strconv.FormatFloat(r.PageRank, 'f', 4, 64), strconv.Itoa(r.Component), strconv.Itoa(r.Community),
+	}
+}
+
+func (r GraphStatRecord) Key() string {
+	return r.ID
+}
+
+// Stable output schema of a line of a link tree (`zk neighbors`, `zk show --graph`)
+type NeighbourRecord struct {
+	ID        string   `json:"id" yaml:"id"`
+	NoteID    string   `json:"note_id" yaml:"note_id"`
+	Title     string   `json:"title" yaml:"title"`
+	Type      string   `json:"type" yaml:"type"`
+	Depth     int      `json:"depth" yaml:"depth"`
+	Parent    string   `json:"parent" yaml:"parent"`       // Note ID of the note it was reached from ("" for the root)
+	Direction string   `json:"direction" yaml:"direction"` // `out` for a link, `in` for a backlink ("" for the root)
+	Rel       string   `json:"rel" yaml:"rel"`
+	Sources   []string `json:"sources" yaml:"sources"`
+	Cycle     bool     `json:"cycle" yaml:"cycle"`
+	Repeated  bool     `json:"repeated" yaml:"repeated"`
+}
+
+func NewNeighbourRecords(lines []TreeLine) []NeighbourRecord {
+	records := make([]NeighbourRecord, 0, len(lines))
+	for _, line := range lines {
+		record := NeighbourRecord{
+			ID:       line.Node.ID,
+			NoteID:   line.Node.NoteID,
+			Title:    line.Node.Title,
+			Type:     line.Node.Type,
+			Depth:    line.Depth,
+			Rel:      line.Edge.Rel,
+			Sources:  line.Edge.Sources,
+			Cycle:    line.Cycle,
+			Repeated: line.Repeated,
+		}
+		if record.Sources == nil {
+			record.Sources = []string{}
+		}
+		if line.Depth > 0 {
+			record.Parent, record.Direction = line.Edge.From, DirectionOut
+			if line.Incoming {
+				record.Parent, record.Direction = line.Edge.To, DirectionIn
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func (r NeighbourRecord) Header() []string {
+	return []string{"id", "note_id", "title", "type", "depth", "parent", "direction", "rel", "sources", "cycle", "repeated"}
+}
+
+func (r NeighbourRecord) Row() []string {
+	return []string{
+		r.ID, r.NoteID, r.Title, r.Type, strconv.Itoa(r.Depth), r.Parent, r.Direction, r.Rel,
+		strings.Join(r.Sources, listSeparator), strconv.FormatBool(r.Cycle), strconv.FormatBool(r.Repeated),
+	}
+}
+
+func (r NeighbourRecord) Key() string {
+	return r.ID
+}
+
+// Write records in a machine-readable format (JSON/YAML lists, CSV/TSV with a header row, or one key per line)
+func WriteRecords[T OutputRecord](w io.Writer, format string, records []T) error {
+	if records == nil {
+		records = []T{}
+	}
+	return writeOutput(w, format, records, records)
+}
+
+// Write a single record (a JSON/YAML object instead of a list)
+func WriteRecord[T OutputRecord](w io.Writer, format string, record T) error {
+	return writeOutput(w, format, record, []T{record})
+}
+
+func writeOutput[T OutputRecord](w io.Writer, format string, value any, records []T) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+
+	case OutputYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	case OutputCSV, OutputTSV:
+		writer := csv.NewWriter(w)
+		if format == OutputTSV {
+			writer.Comma = '\t'
+		}
+		var zero T
+		if err := writer.Write(zero.Header()); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := writer.Write(record.Row()); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case OutputIDs:
+		for _, record := range records {
+			if _, err := fmt.Fprintln(w, record.Key()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return ValidateOutputFormat(format)
+}
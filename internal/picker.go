@@ -0,0 +1,256 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// Returned when a picker is needed but stdin/stdout is not a terminal
+var ErrNotInteractive = errors.New("interactive selection requires a terminal")
+
+// Note offered by the picker
+type PickerItem struct {
+	Zettel Zettel
+	Detail string // Extra text shown after the title, e.g. a search snippet
+}
+
+var (
+	pickerPromptStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	pickerSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	pickerMetaStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	pickerPreviewStyle  = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder(), false, false, false, true).
+				BorderForeground(lipgloss.Color("240")).
+				PaddingLeft(1)
+)
+
+// Check whether stdin and stdout are both terminals
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Picker state
+type pickerModel struct {
+	prompt   string
+	items    []PickerItem
+	matches  []int // Indexes of the items passing the filter, best first
+	query    []rune
+	cursor   int
+	offset   int
+	width    int
+	height   int
+	previews map[string]string
+	chosen   int
+}
+
+func newPickerModel(items []PickerItem, prompt string) pickerModel {
+	m := pickerModel{prompt: prompt, items: items, previews: make(map[string]string), chosen: -1, width: 100, height: 24}
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 && height > 0 {
+		m.width, m.height = width, height
+	}
+	m.filter()
+	return m
+}
+
+// Text matched against the filter
+func pickerHaystack(item PickerItem) string {
+	return fmt.Sprintf("%s %s %s %s", item.Zettel.ID, item.Zettel.Title, strings.Join(item.Zettel.Tags, " "), item.Detail)
+}
+
+// Recompute the matching items for the current query
+func (m *pickerModel) filter() {
+	type scored struct{ index, score int }
+	var results []scored
+	for i, item := range m.items {
+		if score, ok := FuzzyScore(string(m.query), pickerHaystack(item)); ok {
+			results = append(results, scored{i, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	m.matches = m.matches[:0]
+	for _, r := range results {
+		m.matches = append(m.matches, r.index)
+	}
+	m.cursor, m.offset = 0, 0
+}
+
+func (m pickerModel) listHeight() int {
+	return max(m.height-3, 1) // Prompt, blank line and help line
+}
+
+func (m *pickerModel) move(delta int) {
+	m.cursor = min(max(m.cursor+delta, 0), max(len(m.matches)-1, 0))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.listHeight() {
+		m.offset = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.move(0)
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc, tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyEnter:
+			if len(m.matches) > 0 {
+				m.chosen = m.matches[m.cursor]
+			}
+			return m, tea.Quit
+		case tea.KeyUp, tea.KeyCtrlP:
+			m.move(-1)
+		case tea.KeyDown, tea.KeyCtrlN:
+			m.move(1)
+		case tea.KeyPgUp:
+			m.move(-m.listHeight())
+		case tea.KeyPgDown:
+			m.move(m.listHeight())
+		case tea.KeyBackspace:
+			if len(m.query) > 0 {
+				m.query = m.query[:len(m.query)-1]
+				m.filter()
+			}
+		case tea.KeyCtrlU:
+			m.query = nil
+			m.filter()
+		case tea.KeySpace:
+			m.query = append(m.query, ' ')
+			m.filter()
+		case tea.KeyRunes:
+			m.query = append(m.query, msg.Runes...)
+			m.filter()
+		}
+	}
+	return m, nil
+}
+
+// Render the body of a note with glamour (cached per note and width)
+func (m pickerModel) preview(zettel Zettel, width int) string {
+	key := fmt.Sprintf("%s:%d", zettel.NotePath, width)
+	if rendered, ok := m.previews[key]; ok {
+		return rendered
+	}
+
+	rendered := ""
+	content, err := os.ReadFile(zettel.NotePath)
+	if err != nil {
+		rendered = fmt.Sprintf("⚠️ %v", err)
+	} else {
+		_, body, err := ParseFrontMatter(string(content))
+		if err != nil {
+			body = string(content)
+		}
+		renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle("dark"), glamour.WithWordWrap(width))
+		if err == nil {
+			rendered, err = renderer.Render(body)
+		}
+		if err != nil {
+			rendered = body
+		}
+	}
+
+	m.previews[key] = rendered
+	return rendered
+}
+
+func (m pickerModel) View() string {
+	listWidth := max(m.width*2/5, 20)
+	previewWidth := max(m.width-listWidth-2, 10)
+	height := m.listHeight()
+
+	var list strings.Builder
+	end := min(m.offset+height, len(m.matches))
+	for i := m.offset; i < end; i++ {
+		item := m.items[m.matches[i]]
+		line := fmt.Sprintf("[%s] %s", item.Zettel.ID, item.Zettel.Title)
+		if item.Detail != "" {
+			line += " " + pickerMetaStyle.Render(item.Detail)
+		}
+		line = lipgloss.NewStyle().MaxWidth(listWidth).Render(line)
+		if i == m.cursor {
+			line = pickerSelectedStyle.Render(line)
+		}
+		list.WriteString(line)
+		if i < end-1 {
+			list.WriteString("\n")
+		}
+	}
+	if len(m.matches) == 0 {
+		list.WriteString(pickerMetaStyle.Render("No matching notes"))
+	}
+
+	preview := ""
+	if len(m.matches) > 0 {
+		preview = m.preview(m.items[m.matches[m.cursor]].Zettel, previewWidth-2)
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(listWidth).Height(height).MaxHeight(height).Render(list.String()),
+		pickerPreviewStyle.Width(previewWidth).Height(height).MaxHeight(height).MaxWidth(previewWidth+1).Render(preview),
+	)
+
+	var b strings.Builder
+	b.WriteString(pickerPromptStyle.Render(m.prompt+" > ") + string(m.query))
+	b.WriteString(pickerMetaStyle.Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.items))))
+	b.WriteString("\n")
+	b.WriteString(body)
+	b.WriteString("\n")
+	b.WriteString(pickerMetaStyle.Render("type to filter · ↑/↓ select · enter choose · esc cancel"))
+	return b.String()
+}
+
+// Let the user pick a note with a fuzzy-filtered list and a preview pane.
+// Returns nil when the selection is cancelled.
+func PickNote(items []PickerItem, prompt string) (*Zettel, error) {
+	if !IsInteractive() {
+		return nil, ErrNotInteractive
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no notes to choose from")
+	}
+
+	// Log lines would break the TUI layout
+	log.SetOutput(io.Discard)
+	final, err := tea.NewProgram(newPickerModel(items, prompt), tea.WithAltScreen()).Run()
+	log.SetOutput(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run picker: %w", err)
+	}
+
+	m, ok := final.(pickerModel)
+	if !ok || m.chosen < 0 {
+		return nil, nil
+	}
+	zettel := m.items[m.chosen].Zettel
+	return &zettel, nil
+}
+
+// Wrap notes as picker items
+func PickerItems(zettels []Zettel) []PickerItem {
+	items := make([]PickerItem, 0, len(zettels))
+	for _, zettel := range zettels {
+		items = append(items, PickerItem{Zettel: zettel})
+	}
+	return items
+}
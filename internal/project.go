@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const projectTagPrefix = "project:"
+
+// Build the `project:<name>` tag for a project
+func ProjectTag(projectName string) string {
+	return fmt.Sprintf("%s%s", projectTagPrefix, strings.ReplaceAll(strings.TrimSpace(projectName), " ", "_"))
+}
+
+// Normalize a project name for comparison
+func normalizeProjectName(projectName string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(projectName), " ", "_"))
+}
+
+// Get the project names carried by a list of tags
+func ProjectNames(tags []string) []string {
+	var projects []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(strings.ToLower(tag), projectTagPrefix) {
+			projects = append(projects, strings.TrimSpace(tag[len(projectTagPrefix):]))
+		}
+	}
+	return projects
+}
+
+// Check whether a note belongs to a project
+func InProject(zettel Zettel, projectName string) bool {
+	want := normalizeProjectName(projectName)
+	for _, project := range ProjectNames(zettel.Tags) {
+		if normalizeProjectName(project) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Overview of the notes and tasks tagged with a project
+type ProjectSummary struct {
+	Name        string
+	Notes       []Zettel
+	Tasks       []Zettel
+	StatusCount map[string]int
+	Done        int
+	Overdue     []Zettel
+	Recent      []Zettel
+
+	// Markdown checkboxes across the project's notes and tasks
+	ChecklistDone  int
+	ChecklistTotal int
+}
+
+// Percentage of the project's tasks that are done
+func (p ProjectSummary) Progress() int {
+	if len(p.Tasks) == 0 {
+		return 0
+	}
+	return p.Done * 100 / len(p.Tasks)
+}
+
+// Collect the distinct project names used in the index
+func ListProjects(zettels []Zettel) []string {
+	seen := make(map[string]bool)
+	var projects []string
+	for _, zettel := range zettels {
+		if zettel.Deleted {
+			continue
+		}
+		for _, project := range ProjectNames(zettel.Tags) {
+			key := normalizeProjectName(project)
+			if key != "" && !seen[key] {
+				seen[key] = true
+				projects = append(projects, project)
+			}
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return strings.ToLower(projects[i]) < strings.ToLower(projects[j])
+	})
+	return projects
+}
+
+// Summarise a project: notes, task counts by status, checklist progress,
+// overdue tasks and recent activity
+func SummarizeProject(zettels []Zettel, projectName string, config Config, now time.Time, recentLimit int) ProjectSummary {
+	summary := ProjectSummary{
+		Name:        projectName,
+		StatusCount: make(map[string]int),
+	}
+	doneStatus := config.TaskDoneStatus()
+
+	var all []Zettel
+	for _, zettel := range zettels {
+		if zettel.Deleted || !InProject(zettel, projectName) {
+			continue
+		}
+		all = append(all, zettel)
+
+		// Use the spelling found in the tags rather than the one given by the user
+		if summary.Name == projectName {
+			for _, project := range ProjectNames(zettel.Tags) {
+				if normalizeProjectName(project) == normalizeProjectName(projectName) {
+					summary.Name = project
+					break
+				}
+			}
+		}
+
+		if checkboxes, err := LoadCheckboxes(zettel.NotePath); err == nil {
+			done, total := CheckboxProgress(checkboxes)
+			summary.ChecklistDone += done
+			summary.ChecklistTotal += total
+		}
+
+		if zettel.NoteType != "task" {
+			summary.Notes = append(summary.Notes, zettel)
+			continue
+		}
+
+		summary.Tasks = append(summary.Tasks, zettel)
+		summary.StatusCount[zettel.TaskStatus]++
+		if zettel.TaskStatus == doneStatus {
+			summary.Done++
+		}
+		if IsOverdue(zettel, doneStatus, now) {
+			summary.Overdue = append(summary.Overdue, zettel)
+		}
+	}
+
+	SortTasks(summary.Overdue, "due", config.TaskStatuses())
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].UpdatedAt > all[j].UpdatedAt
+	})
+	if recentLimit >= 0 && len(all) > recentLimit {
+		all = all[:recentLimit]
+	}
+	summary.Recent = all
+
+	return summary
+}
+
+// Replace a project tag in a tag list, returning whether anything changed
+func RenameProjectTag(tags []string, oldName, newName string) ([]string, bool) {
+	oldKey := normalizeProjectName(oldName)
+	newTag := ProjectTag(newName)
+
+	changed := false
+	var renamed []string
+	for _, tag := range tags {
+		projects := ProjectNames([]string{tag})
+		if len(projects) == 1 && normalizeProjectName(projects[0]) == oldKey {
+			tag = newTag
+			changed = true
+		}
+		if !containsString(renamed, tag) {
+			renamed = append(renamed, tag)
+		}
+	}
+	return renamed, changed
+}
+
+func containsString(slice []string, item string) bool {
+	for _, val := range slice {
+		if val == item {
+			return true
+		}
+	}
+	return false
+}
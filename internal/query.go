@@ -0,0 +1,492 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Fields accepted in queries, e.g. `type:permanent tag:go created:>2025-01 links:0`
+var QueryFields = []string{
+	"type", "tag", "project", "status", "priority", "id", "title", "alias",
+	"created", "updated", "due", "links", "is",
+}
+
+// Node of a parsed query
+type QueryNode interface {
+	Eval(ctx *QueryContext, zettel Zettel) bool
+	String() string
+}
+
+type andNode struct{ left, right QueryNode }
+type orNode struct{ left, right QueryNode }
+type notNode struct{ node QueryNode }
+
+// `field:value`, `field:>value`, a bare word or a "quoted phrase" (empty field)
+type termNode struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Values shared while evaluating a query over many notes
+type QueryContext struct {
+	Now        time.Time
+	DoneStatus string
+	contents   map[string]string
+}
+
+func NewQueryContext(config Config, now time.Time) *QueryContext {
+	return &QueryContext{Now: now, DoneStatus: config.TaskDoneStatus(), contents: make(map[string]string)}
+}
+
+// Read (and cache) the lower-cased title, aliases and body of a note
+func (ctx *QueryContext) text(zettel Zettel) string {
+	if text, ok := ctx.contents[zettel.NotePath]; ok {
+		return text
+	}
+	text := strings.Join(append([]string{zettel.Title}, zettel.Aliases...), "\n")
+	if content, err := os.ReadFile(zettel.NotePath); err == nil {
+		if _, body, err := ParseFrontMatter(string(content)); err == nil {
+			text += "\n" + body
+		} else {
+			text += "\n" + string(content)
+		}
+	}
+	text = strings.ToLower(text)
+	ctx.contents[zettel.NotePath] = text
+	return text
+}
+
+func (n andNode) Eval(ctx *QueryContext, z Zettel) bool {
+	return n.left.Eval(ctx, z) && n.right.Eval(ctx, z)
+}
+func (n orNode) Eval(ctx *QueryContext, z Zettel) bool {
+	return n.left.Eval(ctx, z) || n.right.Eval(ctx, z)
+}
+func (n notNode) Eval(ctx *QueryContext, z Zettel) bool { return !n.node.Eval(ctx, z) }
+
+func (n andNode) String() string { return fmt.Sprintf("(%s AND %s)", n.left, n.right) }
+func (n orNode) String() string  { return fmt.Sprintf("(%s OR %s)", n.left, n.right) }
+func (n notNode) String() string { return fmt.Sprintf("NOT %s", n.node) }
+func (n termNode) String() string {
+	if n.Field == "" {
+		return strconv.Quote(n.Value)
+	}
+	return fmt.Sprintf("%s:%s%s", n.Field, n.Op, n.Value)
+}
+
+// Compare two ordered values with a query operator
+func compare(op string, cmp int) bool {
+	switch op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// Compare a timestamp with a (possibly partial) date such as `2025`, `2025-01` or `2025-01-15`
+func compareDate(value, op, filter string) bool {
+	if value == "" {
+		return false
+	}
+	if len(value) > len(filter) {
+		value = value[:len(filter)]
+	}
+	return compare(op, strings.Compare(value, filter))
+}
+
+func (n termNode) Eval(ctx *QueryContext, z Zettel) bool {
+	switch n.Field {
+	case "":
+		return strings.Contains(ctx.text(z), strings.ToLower(n.Value))
+	case "type":
+		return strings.EqualFold(z.NoteType, n.Value)
+	case "tag":
+		return HasTag(z.Tags, n.Value)
+	case "project":
+		return InProject(z, n.Value)
+	case "status":
+		return normalizeStatusKey(z.TaskStatus) == normalizeStatusKey(n.Value)
+	case "priority":
+		return strings.EqualFold(z.Priority, n.Value)
+	case "id":
+		return z.ID == n.Value || z.NoteID == n.Value
+	case "title":
+		return strings.Contains(strings.ToLower(z.Title), strings.ToLower(n.Value))
+	case "alias":
+		for _, alias := range z.Aliases {
+			if strings.EqualFold(strings.TrimSpace(alias), n.Value) {
+				return true
+			}
+		}
+		return false
+	case "created":
+		return compareDate(z.CreatedAt, n.Op, n.Value)
+	case "updated":
+		return compareDate(z.UpdatedAt, n.Op, n.Value)
+	case "due":
+		return compareDate(z.Due, n.Op, n.Value)
+	case "links":
+		count, _ := strconv.Atoi(n.Value)
+		return compare(n.Op, len(z.Links)-count)
+	case "is":
+		switch n.Value {
+		case "overdue":
+			return IsOverdue(z, ctx.DoneStatus, ctx.Now)
+		case "done":
+			return z.TaskStatus == ctx.DoneStatus
+		case "archived":
+			return z.Archived
+		case "deleted":
+			return z.Deleted
+		case "task":
+			return z.NoteType == "task"
+		}
+	}
+	return false
+}
+
+// Token of the query lexer
+type queryToken struct {
+	kind  string // word, phrase, lparen, rparen, minus
+	value string
+}
+
+func lexQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: "lparen"})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: "rparen"})
+			i++
+		case r == '-' && (i+1 < len(runes) && !unicode.IsSpace(runes[i+1])):
+			tokens = append(tokens, queryToken{kind: "minus"})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote in query")
+			}
+			tokens = append(tokens, queryToken{kind: "phrase", value: string(runes[i+1 : end])})
+			i = end + 1
+		default:
+			// A word runs until whitespace or a parenthesis; `field:"quoted value"` keeps its spaces
+			var b strings.Builder
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if runes[i] == '"' {
+					end := i + 1
+					for end < len(runes) && runes[end] != '"' {
+						end++
+					}
+					if end >= len(runes) {
+						return nil, fmt.Errorf("unterminated quote in query")
+					}
+					b.WriteString(string(runes[i+1 : end]))
+					i = end + 1
+					continue
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: "word", value: b.String()})
+		}
+	}
+	return tokens, nil
+}
+
+// Recursive descent parser: or := and (OR and)*, and := unary (AND? unary)*, unary := (-|NOT) unary | primary
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *queryParser) parseOr() (QueryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != nil && t.kind == "word" && t.value == "OR"; t = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (QueryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t == nil || t.kind == "rparen" || (t.kind == "word" && t.value == "OR") {
+			return left, nil
+		}
+		if t.kind == "word" && t.value == "AND" {
+			p.pos++
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *queryParser) parseUnary() (QueryNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	if t.kind == "minus" || (t.kind == "word" && t.value == "NOT") {
+		p.pos++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (QueryNode, error) {
+	t := p.peek()
+	p.pos++
+
+	switch t.kind {
+	case "lparen":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != "rparen" {
+			return nil, fmt.Errorf("missing closing parenthesis in query")
+		}
+		p.pos++
+		return node, nil
+	case "rparen":
+		return nil, fmt.Errorf("unexpected ')' in query")
+	case "phrase":
+		return termNode{Value: t.value}, nil
+	}
+
+	return parseTerm(t.value)
+}
+
+// Parse `field:[op]value` or a bare word. Words whose prefix is not a query
+// field (`TODO:`, `https://…`, `12:30`) are free text.
+func parseTerm(word string) (QueryNode, error) {
+	i := strings.Index(word, ":")
+	if i <= 0 {
+		return termNode{Value: word}, nil
+	}
+
+	field := strings.ToLower(word[:i])
+	value := word[i+1:]
+	if indexOf(QueryFields, field) < 0 {
+		return termNode{Value: word}, nil
+	}
+
+	op := "="
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		// A leading `=` of a tag is the exact match prefix (`tag:=lang`), left to TagMatches
+		if field == "tag" && candidate == "=" {
+			break
+		}
+		if strings.HasPrefix(value, candidate) {
+			op = candidate
+			value = value[len(candidate):]
+			break
+		}
+	}
+	if value == "" {
+		return nil, fmt.Errorf("missing value for query field %q", field)
+	}
+
+	switch field {
+	case "links":
+		if _, err := strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid link count %q", value)
+		}
+	case "created", "updated", "due":
+		// Relative dates such as `today` or `-7d`
+		if date, err := ParseDueDate(value, time.Now()); err == nil && !startsWithDigit(value) {
+			value = date
+		}
+	case "is":
+		value = strings.ToLower(value)
+	default:
+		if op != "=" {
+			return nil, fmt.Errorf("operator %q is not supported for field %q", op, field)
+		}
+	}
+
+	return termNode{Field: field, Op: op, Value: value}, nil
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// Parse a query string into an AST (an empty query returns nil)
+func ParseQuery(input string) (QueryNode, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &queryParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token in query at position %d", p.pos+1)
+	}
+	return node, nil
+}
+
+// Collect free-text words and phrases that are not negated (used for ranking)
+func QueryText(node QueryNode) []string {
+	var words []string
+	var walk func(n QueryNode)
+	walk = func(n QueryNode) {
+		switch n := n.(type) {
+		case andNode:
+			walk(n.left)
+			walk(n.right)
+		case orNode:
+			walk(n.left)
+			walk(n.right)
+		case termNode:
+			if n.Field == "" {
+				words = append(words, n.Value)
+			}
+		}
+	}
+	if node != nil {
+		walk(node)
+	}
+	return words
+}
+
+// Check whether a query refers to a field
+func QueryUsesField(node QueryNode, field string) bool {
+	switch n := node.(type) {
+	case andNode:
+		return QueryUsesField(n.left, field) || QueryUsesField(n.right, field)
+	case orNode:
+		return QueryUsesField(n.left, field) || QueryUsesField(n.right, field)
+	case notNode:
+		return QueryUsesField(n.node, field)
+	case termNode:
+		return n.Field == field
+	}
+	return false
+}
+
+// Evaluate a query against the index (a nil query matches every note)
+func FilterByQuery(zettels []Zettel, node QueryNode, ctx *QueryContext) []Zettel {
+	matched := []Zettel{}
+	for _, zettel := range zettels {
+		if node == nil || node.Eval(ctx, zettel) {
+			matched = append(matched, zettel)
+		}
+	}
+	return matched
+}
+
+// Check whether a query has a term with the given field and value
+func queryHasTerm(node QueryNode, field, value string) bool {
+	switch n := node.(type) {
+	case andNode:
+		return queryHasTerm(n.left, field, value) || queryHasTerm(n.right, field, value)
+	case orNode:
+		return queryHasTerm(n.left, field, value) || queryHasTerm(n.right, field, value)
+	case notNode:
+		return queryHasTerm(n.node, field, value)
+	case termNode:
+		return n.Field == field && n.Value == value
+	}
+	return false
+}
+
+// Notes a query runs over. Notes in the trash are only included when the
+// query asks for them (`is:deleted`), and so are archived notes without
+// withArchived (`is:archived`) and tasks without withTasks (`type:`, `is:`).
+func QueryScope(zettels []Zettel, node QueryNode, withArchived, withTasks bool) []Zettel {
+	deleted := queryHasTerm(node, "is", "deleted")
+	withArchived = withArchived || queryHasTerm(node, "is", "archived")
+	withTasks = withTasks || QueryUsesField(node, "type") || QueryUsesField(node, "is")
+
+	scoped := []Zettel{}
+	for _, zettel := range zettels {
+		if zettel.Deleted && !deleted || !zettel.Deleted && zettel.Archived && !withArchived {
+			continue
+		}
+		if zettel.NoteType == "task" && !withTasks {
+			continue
+		}
+		scoped = append(scoped, zettel)
+	}
+	return scoped
+}
+
+// Check whether a query needs the query language: fields, OR, NOT or
+// parentheses grouping anything but plain words
+func IsStructuredQuery(node QueryNode) bool {
+	switch n := node.(type) {
+	case andNode:
+		return IsStructuredQuery(n.left) || IsStructuredQuery(n.right)
+	case termNode:
+		return n.Field != ""
+	case nil:
+		return false
+	}
+	return true
+}
+
+// Run a query over the index (see QueryScope for the notes it looks at)
+func RunQuery(zettels []Zettel, queryString string, config Config) ([]Zettel, error) {
+	node, err := ParseQuery(queryString)
+	if err != nil {
+		return nil, err
+	}
+	return FilterByQuery(QueryScope(zettels, node, false, true), node, NewQueryContext(config, time.Now())), nil
+}
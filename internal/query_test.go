@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLexQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []queryToken
+	}{
+		{"", nil},
+		{"type:task  tag:go", []queryToken{{"word", "type:task"}, {"word", "tag:go"}}},
+		{"-tag:go", []queryToken{{kind: "minus"}, {"word", "tag:go"}}},
+		{"a - b", []queryToken{{"word", "a"}, {"word", "-"}, {"word", "b"}}},
+		{"(a OR b)", []queryToken{{kind: "lparen"}, {"word", "a"}, {"word", "OR"}, {"word", "b"}, {kind: "rparen"}}},
+		{`"machine learning"`, []queryToken{{"phrase", "machine learning"}}},
+		{`title:"two words" x`, []queryToken{{"word", "title:two words"}, {"word", "x"}}},
+		{"日本語 メモ", []queryToken{{"word", "日本語"}, {"word", "メモ"}}},
+	}
+	for _, tt := range tests {
+		got, err := lexQuery(tt.input)
+		if err != nil {
+			t.Errorf("lexQuery(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lexQuery(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{`"open`, `title:"open`} {
+		if _, err := lexQuery(input); err == nil {
+			t.Errorf("lexQuery(%q) should fail on the unterminated quote", input)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // String() of the parsed query
+	}{
+		{"go", `"go"`},
+		{"a b", `("a" AND "b")`},
+		{"a AND b OR c", `(("a" AND "b") OR "c")`},
+		{"a (b OR c)", `("a" AND ("b" OR "c"))`},
+		{"-tag:go", "NOT tag:=go"},
+		{"NOT NOT x", `NOT NOT "x"`},
+		{`"pull request"`, `"pull request"`},
+		{"Type:Task", "type:=Task"},
+		{"is:Overdue", "is:=overdue"},
+		{"created:>2025-01", "created:>2025-01"},
+		{"due:<=2025-03-31", "due:<=2025-03-31"},
+		{"links:>=2", "links:>=2"},
+		{"tag:lang", "tag:=lang"},
+		{"tag:=lang", "tag:==lang"}, // The `=` stays with the tag for an exact match
+		{"tag:=lang/go", "tag:==lang/go"},
+		// Words whose prefix is not a field are free text
+		{"TODO:", `"TODO:"`},
+		{"https://example.com", `"https://example.com"`},
+		{"12:30", `"12:30"`},
+		{":x", `":x"`},
+	}
+	for _, tt := range tests {
+		node, err := ParseQuery(tt.input)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", tt.input, err)
+			continue
+		}
+		if got := node.String(); got != tt.want {
+			t.Errorf("ParseQuery(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	if node, err := ParseQuery("   "); node != nil || err != nil {
+		t.Errorf("ParseQuery of a blank query = %v, %v; want nil, nil", node, err)
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, input := range []string{
+		"(a",
+		"a)",
+		"()",
+		"NOT",
+		"a AND",
+		"a OR",
+		"tag:",
+		"links:many",
+		"title:>x",
+		"tag:>lang",
+		`"open`,
+	} {
+		if node, err := ParseQuery(input); err == nil {
+			t.Errorf("ParseQuery(%q) = %s, want an error", input, node)
+		}
+	}
+}
+
+func TestQueryEval(t *testing.T) {
+	zettels, config, now := loadTaskFixture(t)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"type:task", []string{"Write report", "Review pull request", "Plan trip", "Fix login bug"}},
+		{"tag:code", []string{"Review pull request", "Fix login bug"}},
+		{"tag:=code", []string{"Fix login bug"}},
+		{"tag:code/review", []string{"Review pull request"}},
+		{"project:alpha -type:task", []string{"Reading notes"}},
+		{"status:in-progress", []string{"Review pull request"}},
+		{"priority:medium OR status:waiting", []string{"Plan trip", "Fix login bug"}},
+		{"is:overdue", []string{"Write report"}},
+		{"is:done", []string{"Plan trip"}},
+		{"is:archived", []string{"Ship release"}},
+		{"is:deleted", []string{"Old draft"}},
+		{"is:task project:beta", []string{"Fix login bug"}},
+		{"due:<2025-03-05", []string{"Plan trip"}},
+		{"created:2025-03 -is:task", []string{"Reading notes"}},
+		{"report", []string{"Write report"}},
+		{`"pull request"`, []string{"Review pull request"}},
+		{`title:"login bug"`, []string{"Fix login bug"}},
+		{"(report OR trip) -is:done", []string{"Write report"}},
+		{"id:20250304090000", []string{"Fix login bug"}},
+		{"links:>0", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			node, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matched := FilterByQuery(QueryScope(zettels, node, false, true), node, NewQueryContext(config, now))
+			if got := taskTitles(matched); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryScope(t *testing.T) {
+	zettels := []Zettel{
+		{Title: "Note"},
+		{Title: "Archived note", Archived: true},
+		{Title: "Deleted note", Deleted: true},
+		{Title: "Task", NoteType: "task"},
+		{Title: "Archived task", NoteType: "task", Archived: true},
+	}
+
+	tests := []struct {
+		query        string
+		withArchived bool
+		withTasks    bool
+		want         []string
+	}{
+		{"", false, false, []string{"Note"}},
+		{"", true, false, []string{"Note", "Archived note"}},
+		{"", false, true, []string{"Note", "Task"}},
+		{"tag:go", false, false, []string{"Note"}},
+		{"type:note", false, false, []string{"Note", "Task"}},
+		{"-is:task", false, false, []string{"Note", "Task"}},
+		{"is:archived", false, false, []string{"Note", "Archived note", "Task", "Archived task"}},
+		{"is:deleted", false, false, []string{"Note", "Deleted note", "Task"}},
+		{"is:deleted", true, true, []string{"Note", "Archived note", "Deleted note", "Task", "Archived task"}},
+	}
+	for _, tt := range tests {
+		node, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := taskTitles(QueryScope(zettels, node, tt.withArchived, tt.withTasks))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("QueryScope(%q, %v, %v) = %q, want %q", tt.query, tt.withArchived, tt.withTasks, got, tt.want)
+		}
+	}
+}
+
+func TestQueryInspection(t *testing.T) {
+	tests := []struct {
+		query      string
+		structured bool
+		text       []string
+		usesDue    bool
+	}{
+		{"", false, nil, false},
+		{"go", false, []string{"go"}, false},
+		{"a b", false, []string{"a", "b"}, false},
+		{"(a b) c", false, []string{"a", "b", "c"}, false},
+		{`"a phrase"`, false, []string{"a phrase"}, false},
+		{"a OR b", true, []string{"a", "b"}, false},
+		{"-a", true, nil, false},
+		{"a -due:today", true, []string{"a"}, true},
+		{`a -b (c OR tag:x) "d e"`, true, []string{"a", "c", "d e"}, false},
+	}
+	for _, tt := range tests {
+		node, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := IsStructuredQuery(node); got != tt.structured {
+			t.Errorf("IsStructuredQuery(%q) = %v, want %v", tt.query, got, tt.structured)
+		}
+		if got := QueryText(node); !reflect.DeepEqual(got, tt.text) {
+			t.Errorf("QueryText(%q) = %q, want %q", tt.query, got, tt.text)
+		}
+		if got := QueryUsesField(node, "due"); got != tt.usesDue {
+			t.Errorf("QueryUsesField(%q, due) = %v, want %v", tt.query, got, tt.usesDue)
+		}
+	}
+}
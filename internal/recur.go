@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence rule of a task (`recur:` in front matter)
+type Recurrence struct {
+	Kind     string // daily, weekly, monthly or interval
+	Weekday  time.Weekday
+	Day      int
+	Interval int
+	hasDay   bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Parse a recurrence rule such as `daily`, `weekly on monday`, `monthly on 15` or `every 3 days`
+func ParseRecurrence(rule string) (Recurrence, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(rule)))
+	invalid := fmt.Errorf("invalid recurrence rule %q: use 'daily', 'weekly [on <weekday>]', 'monthly [on <day>]' or 'every N days|weeks'", rule)
+
+	if len(fields) == 0 {
+		return Recurrence{}, invalid
+	}
+
+	switch fields[0] {
+	case "daily":
+		if len(fields) != 1 {
+			return Recurrence{}, invalid
+		}
+		return Recurrence{Kind: "daily"}, nil
+
+	case "weekly":
+		if len(fields) == 1 {
+			return Recurrence{Kind: "weekly"}, nil
+		}
+		if len(fields) != 3 || fields[1] != "on" {
+			return Recurrence{}, invalid
+		}
+		weekday, ok := weekdays[fields[2]]
+		if !ok {
+			return Recurrence{}, invalid
+		}
+		return Recurrence{Kind: "weekly", Weekday: weekday, hasDay: true}, nil
+
+	case "monthly":
+		if len(fields) == 1 {
+			return Recurrence{Kind: "monthly"}, nil
+		}
+		// Accept both `monthly on 15` and `monthly on day 15`
+		if len(fields) == 4 && fields[2] == "day" {
+			fields = []string{fields[0], fields[1], fields[3]}
+		}
+		if len(fields) != 3 || fields[1] != "on" {
+			return Recurrence{}, invalid
+		}
+		day, err := strconv.Atoi(fields[2])
+		if err != nil || day < 1 || day > 31 {
+			return Recurrence{}, invalid
+		}
+		return Recurrence{Kind: "monthly", Day: day, hasDay: true}, nil
+
+	case "every":
+		if len(fields) != 3 {
+			return Recurrence{}, invalid
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 {
+			return Recurrence{}, invalid
+		}
+		switch strings.TrimSuffix(fields[2], "s") {
+		case "day":
+			return Recurrence{Kind: "interval", Interval: n}, nil
+		case "week":
+			return Recurrence{Kind: "interval", Interval: 7 * n}, nil
+		}
+	}
+
+	return Recurrence{}, invalid
+}
+
+// Canonical form of the rule, as written back to front matter
+func (r Recurrence) String() string {
+	switch r.Kind {
+	case "weekly":
+		if r.hasDay {
+			return fmt.Sprintf("weekly on %s", strings.ToLower(r.Weekday.String()))
+		}
+		return "weekly"
+	case "monthly":
+		if r.hasDay {
+			return fmt.Sprintf("monthly on %d", r.Day)
+		}
+		return "monthly"
+	case "interval":
+		if r.Interval == 1 {
+			return "every 1 day"
+		}
+		return fmt.Sprintf("every %d days", r.Interval)
+	}
+	return r.Kind
+}
+
+// Get the first occurrence strictly after `from`
+func (r Recurrence) Next(from time.Time) time.Time {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+
+	switch r.Kind {
+	case "daily":
+		return from.AddDate(0, 0, 1)
+
+	case "weekly":
+		if !r.hasDay {
+			return from.AddDate(0, 0, 7)
+		}
+		days := (int(r.Weekday) - int(from.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return from.AddDate(0, 0, days)
+
+	case "monthly":
+		day := from.Day()
+		if r.hasDay {
+			day = r.Day
+			// The rule day may still be ahead in the current month
+			if from.Day() < clampDay(from.Year(), from.Month(), day) {
+				return time.Date(from.Year(), from.Month(), clampDay(from.Year(), from.Month(), day), 0, 0, 0, 0, from.Location())
+			}
+		}
+		next := time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, from.Location())
+		return time.Date(next.Year(), next.Month(), clampDay(next.Year(), next.Month(), day), 0, 0, 0, 0, from.Location())
+
+	case "interval":
+		return from.AddDate(0, 0, r.Interval)
+	}
+	return from
+}
+
+// Clamp a day to the last day of the month (e.g. 31 -> 30 in April)
+func clampDay(year int, month time.Month, day int) int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		return last
+	}
+	return day
+}
+
+// Relation of the link from a completed recurring task to its next instance
+const NextInstanceRel = "next"
+
+// Check whether a recurring task already spawned its next instance
+func HasNextInstance(links Links) bool {
+	for _, link := range links {
+		if link.Rel == NextInstanceRel {
+			return true
+		}
+	}
+	return false
+}
+
+// Compute the due date of the next instance of a recurring task. A task
+// completed late skips the occurrences already past, so the next instance is
+// never overdue when created.
+func NextDueDate(rule, due string, now time.Time) (string, error) {
+	r, err := ParseRecurrence(rule)
+	if err != nil {
+		return "", err
+	}
+
+	base := now
+	if due != "" {
+		base, err = time.ParseInLocation(DueDateLayout, due, now.Location())
+		if err != nil {
+			return "", fmt.Errorf("invalid due date %q: %w", due, err)
+		}
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := r.Next(base)
+	for !next.After(today) {
+		next = r.Next(next)
+	}
+	return next.Format(DueDateLayout), nil
+}
+
+// Pin a plain `monthly` rule to the day of month of a due date, so that later
+// instances keep that day after a shorter month (31 → 28 → 31)
+func AnchorRecurrence(rule, due string) string {
+	r, err := ParseRecurrence(rule)
+	if err != nil || r.Kind != "monthly" || r.hasDay || due == "" {
+		return rule
+	}
+	t, err := time.Parse(DueDateLayout, due)
+	if err != nil {
+		return rule
+	}
+	r.Day, r.hasDay = t.Day(), true
+	return r.String()
+}
@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func day(date string) time.Time {
+	t, err := time.ParseInLocation(DueDateLayout, date, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string // Canonical form, empty when invalid
+	}{
+		{"daily", "daily"},
+		{" Daily ", "daily"},
+		{"weekly", "weekly"},
+		{"weekly on Fri", "weekly on friday"},
+		{"weekly on monday", "weekly on monday"},
+		{"monthly", "monthly"},
+		{"monthly on 15", "monthly on 15"},
+		{"monthly on day 31", "monthly on 31"},
+		{"every 1 day", "every 1 day"},
+		{"every 3 days", "every 3 days"},
+		{"every 2 weeks", "every 14 days"},
+		{"", ""},
+		{"hourly", ""},
+		{"daily at 9", ""},
+		{"weekly on someday", ""},
+		{"weekly friday", ""},
+		{"monthly on 0", ""},
+		{"monthly on 32", ""},
+		{"every 0 days", ""},
+		{"every 3 months", ""},
+		{"every day", ""},
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.rule)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseRecurrence(%q) = %q, want an error", tt.rule, r)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q): %v", tt.rule, err)
+			continue
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("ParseRecurrence(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	tests := []struct {
+		rule, from, want string
+	}{
+		{"daily", "2025-03-10", "2025-03-11"},
+		{"weekly", "2025-03-05", "2025-03-12"},
+		{"weekly on friday", "2025-02-28", "2025-03-07"}, // Same weekday: a week later
+		{"weekly on monday", "2025-03-05", "2025-03-10"},
+		{"monthly", "2025-03-10", "2025-04-10"},
+		{"monthly", "2025-01-31", "2025-02-28"},
+		{"monthly on 15", "2025-03-10", "2025-03-15"}, // Still ahead this month
+		{"monthly on 15", "2025-03-15", "2025-04-15"},
+		{"monthly on 31", "2025-01-31", "2025-02-28"},
+		{"monthly on 31", "2025-02-28", "2025-03-31"},
+		{"monthly on 31", "2025-04-30", "2025-05-31"},
+		{"every 3 days", "2025-03-30", "2025-04-02"},
+		{"every 2 weeks", "2025-03-10", "2025-03-24"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Next(day(tt.from)).Format(DueDateLayout); got != tt.want {
+			t.Errorf("%q after %s = %s, want %s", tt.rule, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestNextDueDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule, due string
+		now       string
+		want      string
+		wantErr   bool
+	}{
+		{"on time", "weekly on friday", "2025-02-28", "2025-02-28", "2025-03-07", false},
+		{"early", "weekly on friday", "2025-02-28", "2025-02-25", "2025-03-07", false},
+		// A late completion skips the occurrences already past
+		{"late", "weekly on friday", "2025-02-28", "2025-03-20", "2025-03-21", false},
+		{"late, next due today", "weekly on friday", "2025-02-28", "2025-03-07", "2025-03-14", false},
+		{"late monthly", "monthly on 31", "2025-01-31", "2025-04-02", "2025-04-30", false},
+		{"no due date", "daily", "", "2025-03-10", "2025-03-11", false},
+		{"invalid rule", "yearly", "2025-02-28", "2025-03-01", "", true},
+		{"invalid due date", "daily", "2025-02-30", "2025-03-01", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextDueDate(tt.rule, tt.due, day(tt.now).Add(15*time.Hour))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnchorRecurrence(t *testing.T) {
+	tests := []struct {
+		rule, due, want string
+	}{
+		{"monthly", "2025-01-31", "monthly on 31"},
+		{"Monthly", "2025-03-15", "monthly on 15"},
+		{"monthly on 10", "2025-01-31", "monthly on 10"},
+		{"weekly", "2025-01-31", "weekly"},
+		{"monthly", "", "monthly"},
+		{"monthly", "someday", "monthly"},
+		{"yearly", "2025-01-31", "yearly"},
+	}
+	for _, tt := range tests {
+		if got := AnchorRecurrence(tt.rule, tt.due); got != tt.want {
+			t.Errorf("AnchorRecurrence(%q, %q) = %q, want %q", tt.rule, tt.due, got, tt.want)
+		}
+	}
+}
+
+// A plain monthly task due on the 31st keeps its day after February, as each
+// instance is spawned from the anchored rule of the previous one
+func TestMonthlyRecurrenceKeepsDay(t *testing.T) {
+	rule, due := "monthly", "2025-01-31"
+	want := []string{"2025-02-28", "2025-03-31", "2025-04-30", "2025-05-31"}
+	for _, next := range want {
+		rule = AnchorRecurrence(rule, due)
+		got, err := NextDueDate(rule, due, day(due))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != next {
+			t.Fatalf("after %s: got %s, want %s (rule %q)", due, got, next, rule)
+		}
+		due = got
+	}
+}
+
+// Completing a task again (Done → In progress → Done) must not spawn a second
+// instance: the first completion leaves a `next` link
+func TestHasNextInstance(t *testing.T) {
+	tests := []struct {
+		name  string
+		links Links
+		want  bool
+	}{
+		{"no links", nil, false},
+		{"plain links", PlainLinks("20250301090000"), false},
+		{"typed link", Links{{To: "20250301090000", Rel: "supports"}}, false},
+		{"next instance", Links{{To: "20250301090000"}, {To: "20250308090000", Rel: NextInstanceRel}}, true},
+	}
+	for _, tt := range tests {
+		if got := HasNextInstance(tt.links); got != tt.want {
+			t.Errorf("%s: HasNextInstance = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"path"
+	"strings"
+)
+
+// Line of a note changed by a rewrite
+type LineChange struct {
+	Line   int // 1-based line number in the note file
+	Before string
+	After  string
+}
+
+// Rewrite a Markdown or wiki link pointing at the note if its text is the old title
+func retitleLink(link string, target Zettel, oldTitle, newTitle string) string {
+	if strings.HasPrefix(link, "[[") {
+		name, anchor, label, hasAnchor, hasLabel := splitWikiLink(link)
+		if !wikiTargets(name, target) && !strings.EqualFold(strings.TrimSpace(name), oldTitle) {
+			return link
+		}
+		// `[[NoteID|Old Title]]` or `[[Old Title|label]]`
+		if hasLabel && strings.EqualFold(strings.TrimSpace(label), oldTitle) {
+			label = newTitle
+		}
+		if strings.EqualFold(strings.TrimSpace(name), oldTitle) {
+			name = newTitle
+		}
+		return joinWikiLink(name, anchor, label, hasAnchor, hasLabel)
+	}
+
+	text, dest, rest, ok := splitMarkdownLink(link)
+	if !ok || path.Base(dest) != target.NoteID+".md" || !strings.EqualFold(strings.TrimSpace(text), oldTitle) {
+		return link
+	}
+	return "[" + newTitle + "](" + dest + rest + ")"
+}
+
+// Rewrite the links to a retitled note whose text is the old title:
+// `[Old Title](NoteID.md)`, `[[Old Title]]` and `[[NoteID|Old Title]]`.
+// Links with other texts, code blocks and the front matter are left as written.
+func RetitleLinks(content string, target Zettel, oldTitle, newTitle string) (string, []LineChange) {
+	return rewriteBodyLinks(content, func(link string) string {
+		return retitleLink(link, target, oldTitle, newTitle)
+	})
+}
+
+// Rewrite the leading `## Title` heading written when the note was created
+func RetitleHeading(body, oldTitle, newTitle string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		heading, ok := strings.CutPrefix(strings.TrimSpace(line), "## ")
+		if !ok || strings.TrimSpace(heading) != oldTitle {
+			return body, false
+		}
+		lines[i] = "## " + newTitle
+		return strings.Join(lines, "\n"), true
+	}
+	return body, false
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRetitleLinks(t *testing.T) {
+	target := Zettel{NoteID: "20250301090000", Title: "Old Title"}
+	content := strings.Join([]string{
+		"---",
+		`title: "Old Title"`,
+		"---",
+		"",
+		"See [Old Title](20250301090000.md) and [other text](20250301090000.md).",
+		"Wiki [[Old Title]], [[old title#Intro|Old Title]], [[20250301090000|Old Title]] and [[20250301090000|label]].",
+		"Elsewhere [Old Title](20250302090000.md) and [[Other]].",
+		"```",
+		"[Old Title](20250301090000.md)",
+		"```",
+		"[Old Title](notes/20250301090000.md#sec)",
+	}, "\n")
+
+	got, changes := RetitleLinks(content, target, "Old Title", "New Title")
+
+	want := strings.Join([]string{
+		"---",
+		`title: "Old Title"`,
+		"---",
+		"",
+		"See [New Title](20250301090000.md) and [other text](20250301090000.md).",
+		"Wiki [[New Title]], [[New Title#Intro|New Title]], [[20250301090000|New Title]] and [[20250301090000|label]].",
+		"Elsewhere [Old Title](20250302090000.md) and [[Other]].",
+		"```",
+		"[Old Title](20250301090000.md)",
+		"```",
+		"[New Title](notes/20250301090000.md#sec)",
+	}, "\n")
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	var lines []int
+	for _, change := range changes {
+		lines = append(lines, change.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{5, 6, 11}) {
+		t.Errorf("changed lines = %v, want [5 6 11]", lines)
+	}
+}
+
+func TestRetitleHeading(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		changed bool
+	}{
+		{"leading heading", "\n## Old Title\n\ntext", "\n## New Title\n\ntext", true},
+		{"indented", "  ## Old Title  \ntext", "## New Title\ntext", true},
+		{"other heading", "## Another\n## Old Title", "## Another\n## Old Title", false},
+		{"other level", "# Old Title\n", "# Old Title\n", false},
+		{"text first", "text\n## Old Title", "text\n## Old Title", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := RetitleHeading(tt.body, "Old Title", "New Title")
+			if got != tt.want || changed != tt.changed {
+				t.Errorf("got %q, %v; want %q, %v", got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Returned when the user cancels the disambiguation prompt
+var ErrSelectionCancelled = errors.New("selection cancelled")
+
+// Find the notes a reference may point to, among the notes kept by `among`.
+// The first kind of match that finds anything wins: short ID,
+// full note ID (optionally with `.md`), exact title or alias, title or alias
+// fragment, then fuzzy title or alias. `exact` tells whether the candidates
+// were found by one of the first three.
+func FindNoteCandidates(zettels []Zettel, ref string, among func(z Zettel) bool) (candidates []int, exact bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, false
+	}
+	noteID := strings.TrimSuffix(ref, ".md")
+
+	matchers := []func(z Zettel) bool{
+		func(z Zettel) bool { return z.ID == ref },
+		func(z Zettel) bool { return z.NoteID == noteID },
+		func(z Zettel) bool {
+			for _, name := range NoteNames(z) {
+				if strings.EqualFold(name, ref) {
+					return true
+				}
+			}
+			return false
+		},
+		func(z Zettel) bool {
+			for _, name := range NoteNames(z) {
+				if strings.Contains(strings.ToLower(name), strings.ToLower(ref)) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	for m, match := range matchers {
+		var found []int
+		for i, z := range zettels {
+			if among(z) && match(z) {
+				found = append(found, i)
+			}
+		}
+		if len(found) > 0 {
+			return found, m < 3
+		}
+	}
+
+	// Fuzzy title or alias match, best first
+	type scored struct{ index, score int }
+	var fuzzy []scored
+	for i, z := range zettels {
+		if !among(z) {
+			continue
+		}
+		best, matched := 0, false
+		for _, name := range NoteNames(z) {
+			if score, ok := FuzzyScore(ref, name); ok && (!matched || score > best) {
+				best, matched = score, true
+			}
+		}
+		if matched {
+			fuzzy = append(fuzzy, scored{i, best})
+		}
+	}
+	sort.SliceStable(fuzzy, func(i, j int) bool { return fuzzy[i].score > fuzzy[j].score })
+
+	found := make([]int, 0, len(fuzzy))
+	for _, f := range fuzzy {
+		found = append(found, f.index)
+	}
+	return found, false
+}
+
+// Resolve a note reference to the index of a single note outside the trash.
+// Only a single exact match (ID, note ID, title or alias) is taken as is:
+// for several matches, or title fragments and fuzzy matches, the user picks
+// the note interactively; without a terminal an error lists the candidates.
+func ResolveNote(zettels []Zettel, ref string) (int, error) {
+	return ResolveNoteAmong(zettels, ref, func(z Zettel) bool { return !z.Deleted })
+}
+
+// Resolve a note reference among the notes kept by `among`, e.g. the notes
+// in the trash or the archive for `zk restore`
+func ResolveNoteAmong(zettels []Zettel, ref string, among func(z Zettel) bool) (int, error) {
+	candidates, exact := FindNoteCandidates(zettels, ref, among)
+	if len(candidates) == 0 {
+		return -1, fmt.Errorf("no note matches %q", ref)
+	}
+	if exact && len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	message := fmt.Sprintf("%q matches %d notes", ref, len(candidates))
+	if len(candidates) == 1 {
+		message = fmt.Sprintf("%q only partly matches a note title", ref)
+	}
+
+	if !IsInteractive() {
+		lines := []string{}
+		for _, i := range candidates {
+			lines = append(lines, fmt.Sprintf("  [%s] %s", zettels[i].ID, zettels[i].Title))
+		}
+		return -1, fmt.Errorf("%s; use an ID or the full title:\n%s", message, strings.Join(lines, "\n"))
+	}
+
+	items := make([]PickerItem, 0, len(candidates))
+	for _, i := range candidates {
+		items = append(items, PickerItem{Zettel: zettels[i]})
+	}
+	picked, err := PickNote(items, message)
+	if err != nil {
+		return -1, err
+	}
+	if picked == nil {
+		return -1, ErrSelectionCancelled
+	}
+	for _, i := range candidates {
+		if zettels[i].NoteID == picked.NoteID {
+			return i, nil
+		}
+	}
+	return -1, ErrSelectionCancelled
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Named query stored in `queries.yaml`
+type SavedQuery struct {
+	Name   string `yaml:"name"`
+	Query  string `yaml:"query"`
+	NoteID string `yaml:"note_id,omitempty"` // Index note regenerated from the query
+}
+
+// Location of the saved queries file (next to `zettel.json` unless configured)
+func SavedQueriesPath(config Config) string {
+	if config.SavedQueries != "" {
+		return config.SavedQueries
+	}
+	return filepath.Join(filepath.Dir(config.ZettelJson), "queries.yaml")
+}
+
+// Load saved queries (a missing file means no saved queries)
+func LoadSavedQueries(config Config) ([]SavedQuery, error) {
+	data, err := os.ReadFile(SavedQueriesPath(config))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read saved queries: %w", err)
+	}
+
+	var queries []SavedQuery
+	if err := yaml.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse saved queries: %w", err)
+	}
+	return queries, nil
+}
+
+// Write saved queries back to `queries.yaml`
+func SaveSavedQueries(queries []SavedQuery, config Config) error {
+	data, err := yaml.Marshal(queries)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to convert saved queries to YAML: %w", err)
+	}
+	if err := os.WriteFile(SavedQueriesPath(config), data, 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write saved queries: %w", err)
+	}
+	return nil
+}
+
+// Find a saved query by name
+func FindSavedQuery(queries []SavedQuery, name string) int {
+	for i, q := range queries {
+		if q.Name == name {
+			return i
+		}
+	}
+	return -1
+}
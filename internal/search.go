@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Fields indexed for each note and their weight in the ranking
+var SearchFieldWeights = map[string]float64{
+	"title": 3.0,
+	"tags":  2.0,
+	"body":  1.0,
+}
+
+// Format of the index cache; older caches are rebuilt
+const searchIndexVersion = 2
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Tokenised note, as stored in the index cache
+type SearchDocument struct {
+	NoteID  string              `json:"note_id"`
+	ModTime int64               `json:"mod_time"`
+	Fields  map[string][]string `json:"fields"`
+}
+
+// Inverted index over note titles (and aliases), tags and bodies
+type SearchIndex struct {
+	Version   int                        `json:"version"`
+	Tokenizer string                     `json:"tokenizer"`
+	Documents map[string]*SearchDocument `json:"documents"`
+
+	postings map[string]map[string]map[string]int // field -> term -> note ID -> term frequency
+	avgLen   map[string]float64
+}
+
+// Ranked search result
+type SearchHit struct {
+	NoteID string
+	Score  float64
+	Terms  []string
+}
+
+// Location of the index cache (next to `zettel.json`)
+func SearchIndexPath(config Config) string {
+	return filepath.Join(filepath.Dir(config.ZettelJson), "search_index.json")
+}
+
+func loadSearchIndexCache(cachePath string) *SearchIndex {
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil
+	}
+	var index SearchIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		log.Printf("⚠️ Ignoring broken search index cache: %v", err)
+		return nil
+	}
+	return &index
+}
+
+// Build the search index for the given notes, reusing cached tokens of unchanged files
+func BuildSearchIndex(zettels []Zettel, config Config) (*SearchIndex, error) {
+	cachePath := SearchIndexPath(config)
+	tokenizer := DetectTokenizer()
+
+	cached := loadSearchIndexCache(cachePath)
+	if cached == nil || cached.Tokenizer != tokenizer || cached.Version != searchIndexVersion {
+		cached = &SearchIndex{Documents: map[string]*SearchDocument{}}
+	}
+
+	index := &SearchIndex{Version: searchIndexVersion, Tokenizer: tokenizer, Documents: map[string]*SearchDocument{}}
+	changed := len(cached.Documents) == 0
+
+	for _, zettel := range zettels {
+		info, err := os.Stat(zettel.NotePath)
+		if err != nil {
+			log.Printf("⚠️ Failed to stat note: %s (%v)", zettel.NotePath, err)
+			continue
+		}
+
+		if doc, ok := cached.Documents[zettel.NoteID]; ok && doc.ModTime == info.ModTime().UnixNano() {
+			index.Documents[zettel.NoteID] = doc
+			continue
+		}
+
+		content, err := os.ReadFile(zettel.NotePath)
+		if err != nil {
+			log.Printf("⚠️ Failed to read note: %s (%v)", zettel.NotePath, err)
+			continue
+		}
+
+		frontMatter, body, err := ParseFrontMatter(string(content))
+		if err != nil {
+			body = string(content)
+			frontMatter = FrontMatter{Title: zettel.Title, Tags: zettel.Tags, Aliases: zettel.Aliases}
+		}
+
+		index.Documents[zettel.NoteID] = &SearchDocument{
+			NoteID:  zettel.NoteID,
+			ModTime: info.ModTime().UnixNano(),
+			Fields: map[string][]string{
+				"title": Tokenize(strings.Join(append([]string{frontMatter.Title}, frontMatter.Aliases...), " "), tokenizer),
+				"tags":  Tokenize(strings.Join(frontMatter.Tags, " "), tokenizer),
+				"body":  Tokenize(body, tokenizer),
+			},
+		}
+		changed = true
+	}
+
+	if changed || len(cached.Documents) != len(index.Documents) {
+		if err := index.save(cachePath); err != nil {
+			log.Printf("⚠️ Failed to save search index cache: %v", err)
+		}
+	}
+
+	index.buildPostings()
+	return index, nil
+}
+
+func (index *SearchIndex) save(cachePath string) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to convert search index to JSON: %w", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// Build the in-memory inverted index from the tokenised documents
+func (index *SearchIndex) buildPostings() {
+	index.postings = make(map[string]map[string]map[string]int)
+	index.avgLen = make(map[string]float64)
+
+	for field := range SearchFieldWeights {
+		index.postings[field] = make(map[string]map[string]int)
+		total := 0
+		for noteID, doc := range index.Documents {
+			terms := doc.Fields[field]
+			total += len(terms)
+			for _, term := range terms {
+				if index.postings[field][term] == nil {
+					index.postings[field][term] = make(map[string]int)
+				}
+				index.postings[field][term][noteID]++
+			}
+		}
+		if len(index.Documents) > 0 {
+			index.avgLen[field] = float64(total) / float64(len(index.Documents))
+		}
+	}
+}
+
+// Inverse document frequency of a term (BM25 variant, always positive)
+func (index *SearchIndex) idf(field, term string) float64 {
+	n := float64(len(index.Documents))
+	df := float64(len(index.postings[field][term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// Rank notes for a query with BM25 over the weighted fields
+func (index *SearchIndex) Search(query string, fields []string) []SearchHit {
+	terms := removeDuplicates(Tokenize(query, index.Tokenizer))
+	if len(terms) == 0 {
+		return nil
+	}
+	if len(fields) == 0 {
+		for field := range SearchFieldWeights {
+			fields = append(fields, field)
+		}
+	}
+
+	scores := make(map[string]float64)
+	matched := make(map[string][]string)
+
+	for _, term := range terms {
+		for _, field := range fields {
+			idf := index.idf(field, term)
+			for noteID, tf := range index.postings[field][term] {
+				docLen := float64(len(index.Documents[noteID].Fields[field]))
+				norm := 1 - bm25B + bm25B*docLen/math.Max(index.avgLen[field], 1)
+				score := idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*norm)
+				scores[noteID] += SearchFieldWeights[field] * score
+				if !containsString(matched[noteID], term) {
+					matched[noteID] = append(matched[noteID], term)
+				}
+			}
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for noteID, score := range scores {
+		hits = append(hits, SearchHit{NoteID: noteID, Score: score, Terms: matched[noteID]})
+	}
+
+	// Highest score first; ties are broken by note ID so the order is stable
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].NoteID < hits[j].NoteID
+	})
+	return hits
+}
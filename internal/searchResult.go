@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Default number of characters shown around a match
+const DefaultSnippetWidth = 80
+
+// Byte offsets of a match inside a line
+type MatchSpan struct {
+	Start int
+	End   int
+}
+
+// Line of a note matching a search
+type MatchLine struct {
+	Line    int // 1-based line number in the note file
+	Text    string
+	Spans   []MatchSpan
+	Context bool // Context line shown around a match
+}
+
+// Search results of a single note
+type NoteResult struct {
+	Zettel Zettel
+	Score  float64
+	Lines  []MatchLine
+}
+
+// Number of matching (non-context) lines
+func (r NoteResult) MatchCount() int {
+	count := 0
+	for _, line := range r.Lines {
+		if !line.Context {
+			count++
+		}
+	}
+	return count
+}
+
+// Texts of the matching lines
+func (r NoteResult) MatchTexts() []string {
+	texts := []string{}
+	for _, line := range r.Lines {
+		if !line.Context {
+			texts = append(texts, strings.TrimSpace(line.Text))
+		}
+	}
+	return texts
+}
+
+// Order results by score, then number of matching lines, then note ID
+func SortNoteResults(results []NoteResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if ci, cj := results[i].MatchCount(), results[j].MatchCount(); ci != cj {
+			return ci > cj
+		}
+		if results[i].Zettel.NoteID != results[j].Zettel.NoteID {
+			return results[i].Zettel.NoteID < results[j].Zettel.NoteID
+		}
+		return results[i].Zettel.NotePath < results[j].Zettel.NotePath
+	})
+}
+
+// Find the spans of any of the terms in a line (case-insensitive)
+func FindMatchSpans(text string, terms []string) []MatchSpan {
+	quoted := []string{}
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	// Longer terms first so that overlapping terms match as a whole
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	pattern := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+
+	var spans []MatchSpan
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		spans = append(spans, MatchSpan{Start: loc[0], End: loc[1]})
+	}
+	return spans
+}
+
+// Find the lines of a note containing any of the terms, skipping front matter
+func FindMatchLines(content string, terms []string, max int) []MatchLine {
+	lines := strings.Split(content, "\n")
+
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	var matches []MatchLine
+	for i := start; i < len(lines); i++ {
+		text := strings.TrimRight(lines[i], "\r")
+		spans := FindMatchSpans(text, terms)
+		if len(spans) == 0 {
+			continue
+		}
+		matches = append(matches, MatchLine{Line: i + 1, Text: text, Spans: spans})
+		if max > 0 && len(matches) >= max {
+			break
+		}
+	}
+	return matches
+}
+
+// Read a note and find the lines containing any of the terms
+func LoadMatchLines(notePath string, terms []string, max int) []MatchLine {
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return nil
+	}
+	return FindMatchLines(string(content), terms, max)
+}
+
+// Cut a line down to `width` characters around its first match and highlight the match spans
+func FormatSnippet(text string, spans []MatchSpan, width int, highlight func(a ...interface{}) string) string {
+	runes := []rune(text)
+
+	// Convert byte offsets to rune offsets
+	runeIndex := make(map[int]int, len(runes)+1)
+	offset := 0
+	for i, r := range runes {
+		runeIndex[offset] = i
+		offset += len(string(r))
+	}
+	runeIndex[offset] = len(runes)
+
+	type runeSpan struct{ start, end int }
+	var marks []runeSpan
+	for _, span := range spans {
+		start, okStart := runeIndex[span.Start]
+		end, okEnd := runeIndex[span.End]
+		if okStart && okEnd && start < end {
+			marks = append(marks, runeSpan{start, end})
+		}
+	}
+
+	// Skip indentation unless a match starts inside it
+	from, to := 0, len(runes)
+	for from < to && (runes[from] == ' ' || runes[from] == '\t') && (len(marks) == 0 || from < marks[0].start) {
+		from++
+	}
+
+	// Keep the first match about a third of the way into the window
+	if width > 0 && to-from > width {
+		if len(marks) > 0 && marks[0].start-width/3 > from {
+			from = marks[0].start - width/3
+		}
+		if from+width < to {
+			to = from + width
+		} else {
+			from = to - width
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 && strings.TrimSpace(string(runes[:from])) != "" {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, mark := range marks {
+		start, end := max(mark.start, pos), min(mark.end, to)
+		if start >= end {
+			continue
+		}
+		b.WriteString(string(runes[pos:start]))
+		b.WriteString(highlight(string(runes[start:end])))
+		pos = end
+	}
+	b.WriteString(string(runes[pos:to]))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// Print search results grouped per note
+func DisplaySearchResults(results []NoteResult, width int) {
+	titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
+	metaStyle := color.New(color.FgHiBlack).SprintFunc()
+	matchStyle := color.New(color.FgHiYellow, color.Bold).SprintFunc()
+
+	fmt.Printf("\n🔍 Search Results (%d notes):\n\n", len(results))
+	for _, result := range results {
+		zettel := result.Zettel
+
+		meta := []string{}
+		if zettel.NoteType != "" {
+			meta = append(meta, zettel.NoteType)
+		}
+		if result.Score > 0 {
+			meta = append(meta, fmt.Sprintf("score %.2f", result.Score))
+		}
+		if count := result.MatchCount(); count == 1 {
+			meta = append(meta, "1 match")
+		} else if count > 1 {
+			meta = append(meta, fmt.Sprintf("%d matches", count))
+		}
+
+		id := zettel.ID
+		if id == "" {
+			id = "?"
+		}
+		fmt.Printf("📄 [%s] %s", id, titleStyle(zettel.Title))
+		if len(meta) > 0 {
+			fmt.Printf(" %s", metaStyle("("+strings.Join(meta, ", ")+")"))
+		}
+		fmt.Println()
+
+		for _, line := range result.Lines {
+			number := metaStyle(fmt.Sprintf("%5d:", line.Line))
+			if line.Context {
+				fmt.Printf("  %s %s\n", number, metaStyle(FormatSnippet(line.Text, nil, width, metaStyle)))
+				continue
+			}
+			fmt.Printf("  %s %s\n", number, FormatSnippet(line.Text, line.Spans, width, matchStyle))
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,224 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Folgezettel sequence: alternating numbers and letters (`1`, `1a`, `1a1`, `1a2b`)
+var sequencePattern = regexp.MustCompile(`^[1-9][0-9]*([a-z]+[1-9][0-9]*)*[a-z]*$`)
+var sequenceSegmentPattern = regexp.MustCompile(`[0-9]+|[a-z]+`)
+
+// Check whether a sequence is well formed
+func ValidateSequence(sequence string) error {
+	if !sequencePattern.MatchString(sequence) {
+		return fmt.Errorf("invalid sequence %q: must alternate numbers and letters, like 1, 1a or 1a2", sequence)
+	}
+	return nil
+}
+
+// Normalize a sequence as written in the front matter
+func NormalizeSequence(sequence string) string {
+	return strings.ToLower(strings.TrimSpace(sequence))
+}
+
+// Split a sequence into its number and letter segments
+func sequenceSegments(sequence string) []string {
+	return sequenceSegmentPattern.FindAllString(sequence, -1)
+}
+
+func isNumberSegment(segment string) bool {
+	return segment != "" && segment[0] >= '0' && segment[0] <= '9'
+}
+
+// Parent position of a sequence (`1a1` → `1a`), empty for top-level positions
+func SequenceParent(sequence string) string {
+	segments := sequenceSegments(sequence)
+	if len(segments) <= 1 {
+		return ""
+	}
+	return strings.Join(segments[:len(segments)-1], "")
+}
+
+// Number of segments of a sequence (`1` → 1, `1a1` → 3)
+func SequenceDepth(sequence string) int {
+	return len(sequenceSegments(sequence))
+}
+
+// Next value of a segment: `1` → `2`, `a` → `b`, `z` → `aa`
+func nextSegment(segment string) string {
+	if isNumberSegment(segment) {
+		n, _ := strconv.Atoi(segment)
+		return strconv.Itoa(n + 1)
+	}
+	letters := []byte(segment)
+	for i := len(letters) - 1; i >= 0; i-- {
+		if letters[i] < 'z' {
+			letters[i]++
+			return string(letters)
+		}
+		letters[i] = 'a'
+	}
+	return "a" + string(letters)
+}
+
+func compareSegments(a, b string) int {
+	if isNumberSegment(a) != isNumberSegment(b) {
+		if isNumberSegment(a) {
+			return -1
+		}
+		return 1
+	}
+	if isNumberSegment(a) {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	}
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// Compare two sequences in reading order (`1` < `1a` < `1a1` < `1b` < `2` < `10`)
+func CompareSequences(a, b string) int {
+	x, y := sequenceSegments(a), sequenceSegments(b)
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if c := compareSegments(x[i], y[i]); c != 0 {
+			return c
+		}
+	}
+	return len(x) - len(y)
+}
+
+// Sequences in use by the notes
+func usedSequences(zettels []Zettel) map[string]bool {
+	used := make(map[string]bool)
+	for _, z := range zettels {
+		if z.Sequence != "" {
+			used[NormalizeSequence(z.Sequence)] = true
+		}
+	}
+	return used
+}
+
+// Next free top-level position (one past the highest top-level number)
+func NextTopSequence(zettels []Zettel) string {
+	highest := 0
+	for sequence := range usedSequences(zettels) {
+		segments := sequenceSegments(sequence)
+		if len(segments) > 0 && isNumberSegment(segments[0]) {
+			if n, _ := strconv.Atoi(segments[0]); n > highest {
+				highest = n
+			}
+		}
+	}
+	return strconv.Itoa(highest + 1)
+}
+
+// Position continuing a note's train: the next free sibling (`1a` → `1b`)
+func NextSequenceAfter(sequence string, zettels []Zettel) string {
+	segments := sequenceSegments(sequence)
+	if len(segments) == 0 {
+		return ""
+	}
+	used := usedSequences(zettels)
+	prefix := strings.Join(segments[:len(segments)-1], "")
+	next := nextSegment(segments[len(segments)-1])
+	for used[prefix+next] {
+		next = nextSegment(next)
+	}
+	return prefix + next
+}
+
+// Position branching off a note: one past its last child (`1a` → `1a1`, or `1a3` when `1a2` exists)
+func NextSequenceBranch(sequence string, zettels []Zettel) string {
+	segments := sequenceSegments(sequence)
+	if len(segments) == 0 {
+		return ""
+	}
+	first := "a"
+	if !isNumberSegment(segments[len(segments)-1]) {
+		first = "1"
+	}
+
+	last := ""
+	for used := range usedSequences(zettels) {
+		if SequenceParent(used) == sequence {
+			child := sequenceSegments(used)
+			if segment := child[len(child)-1]; last == "" || compareSegments(segment, last) > 0 {
+				last = segment
+			}
+		}
+	}
+	if last == "" {
+		return sequence + first
+	}
+	return sequence + nextSegment(last)
+}
+
+// Node of the sequence hierarchy. Missing positions between a note and its
+// nearest ancestor are kept as nodes without notes.
+type SequenceNode struct {
+	Sequence string
+	Notes    []Zettel // Empty for a missing position; several when a position is used twice
+	Children []*SequenceNode
+}
+
+// Build the sequence hierarchy of the notes having a valid sequence, in reading order
+func SequenceTree(zettels []Zettel) []*SequenceNode {
+	nodes := make(map[string]*SequenceNode)
+	var get func(sequence string) *SequenceNode
+	var roots []*SequenceNode
+	get = func(sequence string) *SequenceNode {
+		if node, ok := nodes[sequence]; ok {
+			return node
+		}
+		node := &SequenceNode{Sequence: sequence}
+		nodes[sequence] = node
+		if parent := SequenceParent(sequence); parent != "" {
+			p := get(parent)
+			p.Children = append(p.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+		return node
+	}
+
+	for _, z := range zettels {
+		sequence := NormalizeSequence(z.Sequence)
+		if sequence == "" || ValidateSequence(sequence) != nil {
+			continue
+		}
+		node := get(sequence)
+		node.Notes = append(node.Notes, z)
+	}
+
+	var sortNodes func(list []*SequenceNode)
+	sortNodes = func(list []*SequenceNode) {
+		sort.Slice(list, func(i, j int) bool { return CompareSequences(list[i].Sequence, list[j].Sequence) < 0 })
+		for _, node := range list {
+			sortNodes(node.Children)
+		}
+	}
+	sortNodes(roots)
+	return roots
+}
+
+// Find the node of a position in the hierarchy
+func FindSequenceNode(roots []*SequenceNode, sequence string) *SequenceNode {
+	for _, node := range roots {
+		if node.Sequence == sequence {
+			return node
+		}
+		if strings.HasPrefix(sequence, node.Sequence) {
+			if found := FindSequenceNode(node.Children, sequence); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Notes at the given sequence positions
+func sequenceNotes(sequences ...string) []Zettel {
+	zettels := []Zettel{}
+	for _, sequence := range sequences {
+		zettels = append(zettels, Zettel{Sequence: sequence})
+	}
+	return zettels
+}
+
+func TestValidateSequence(t *testing.T) {
+	tests := []struct {
+		sequence string
+		valid    bool
+	}{
+		{"1", true},
+		{"1a", true},
+		{"1a2", true},
+		{"12aa3b", true},
+		{"", false},
+		{"0", false},
+		{"a1", false},
+		{"1a0", false},
+		{"1A", false},
+		{"1-a", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateSequence(tt.sequence); (err == nil) != tt.valid {
+			t.Errorf("ValidateSequence(%q) = %v, want valid %v", tt.sequence, err, tt.valid)
+		}
+	}
+}
+
+func TestSequenceParent(t *testing.T) {
+	tests := []struct {
+		sequence, parent string
+		depth            int
+	}{
+		{"1", "", 1},
+		{"1a", "1", 2},
+		{"1a12", "1a", 3},
+		{"10aa", "10", 2},
+	}
+	for _, tt := range tests {
+		if got := SequenceParent(tt.sequence); got != tt.parent {
+			t.Errorf("SequenceParent(%q) = %q, want %q", tt.sequence, got, tt.parent)
+		}
+		if got := SequenceDepth(tt.sequence); got != tt.depth {
+			t.Errorf("SequenceDepth(%q) = %d, want %d", tt.sequence, got, tt.depth)
+		}
+	}
+}
+
+func TestCompareSequences(t *testing.T) {
+	want := []string{"1", "1a", "1a1", "1a2", "1a10", "1b", "1z", "1aa", "2", "10"}
+	got := []string{"10", "1aa", "1a10", "2", "1b", "1a2", "1", "1z", "1a1", "1a"}
+	sort.Slice(got, func(i, j int) bool { return CompareSequences(got[i], got[j]) < 0 })
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted = %q, want %q", got, want)
+	}
+	if CompareSequences("1a", "1a") != 0 {
+		t.Error("a sequence should equal itself")
+	}
+}
+
+func TestNextSegment(t *testing.T) {
+	tests := map[string]string{
+		"1":  "2",
+		"9":  "10",
+		"a":  "b",
+		"y":  "z",
+		"z":  "aa",
+		"az": "ba",
+		"zz": "aaa",
+	}
+	for segment, want := range tests {
+		if got := nextSegment(segment); got != want {
+			t.Errorf("nextSegment(%q) = %q, want %q", segment, got, want)
+		}
+	}
+}
+
+func TestNextSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		used    []string
+		after   string
+		want    string
+		branch  string
+		wantBr  string
+		wantTop string
+	}{
+		{"empty vault", nil, "1", "2", "1", "1a", "1"},
+		{"sibling taken", []string{"1", "2", "1a"}, "1", "3", "1", "1b", "3"},
+		{"letters wrap", []string{"1", "1z"}, "1z", "1aa", "1", "1aa", "2"},
+		{"branch off letters", []string{"1a", "1a1", "1a2"}, "1a1", "1a3", "1a", "1a3", "2"},
+		{"gap in children", []string{"3", "3a", "3c"}, "3a", "3b", "3", "3d", "4"},
+		{"case and spaces", []string{" 4A "}, "4", "5", "4", "4b", "5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zettels := sequenceNotes(tt.used...)
+			if got := NextSequenceAfter(tt.after, zettels); got != tt.want {
+				t.Errorf("NextSequenceAfter(%q) = %q, want %q", tt.after, got, tt.want)
+			}
+			if got := NextSequenceBranch(tt.branch, zettels); got != tt.wantBr {
+				t.Errorf("NextSequenceBranch(%q) = %q, want %q", tt.branch, got, tt.wantBr)
+			}
+			if got := NextTopSequence(zettels); got != tt.wantTop {
+				t.Errorf("NextTopSequence = %q, want %q", got, tt.wantTop)
+			}
+		})
+	}
+}
+
+func TestSequenceTree(t *testing.T) {
+	zettels := []Zettel{
+		{Title: "Two", Sequence: "2"},
+		{Title: "One", Sequence: "1"},
+		{Title: "Deep", Sequence: "1a1"}, // 1a is missing
+		{Title: "One B", Sequence: "1b"},
+		{Title: "Unplaced"},
+	}
+	roots := SequenceTree(zettels)
+
+	var walk func(nodes []*SequenceNode) []string
+	walk = func(nodes []*SequenceNode) []string {
+		var lines []string
+		for _, node := range nodes {
+			title := "-"
+			if len(node.Notes) > 0 {
+				title = node.Notes[0].Title
+			}
+			lines = append(lines, node.Sequence+" "+title)
+			lines = append(lines, walk(node.Children)...)
+		}
+		return lines
+	}
+	want := []string{"1 One", "1a -", "1a1 Deep", "1b One B", "2 Two"}
+	if got := walk(roots); !reflect.DeepEqual(got, want) {
+		t.Errorf("tree = %q, want %q", got, want)
+	}
+	if node := FindSequenceNode(roots, "1a1"); node == nil || node.Notes[0].Title != "Deep" {
+		t.Errorf("FindSequenceNode(1a1) = %+v", node)
+	}
+}
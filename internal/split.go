@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// Heading section of a note body: the heading and the lines up to the next
+// heading of the same or a higher level
+type NoteSection struct {
+	Title string
+	Level int // Number of `#`
+	Start int // Line index of the heading in the body
+	End   int // Line index after the last line of the section
+}
+
+// Heading sections of a note body, outside code blocks. The leading heading
+// repeating the note title is not a section.
+func NoteSections(body, title string) []NoteSection {
+	lines := strings.Split(body, "\n")
+	var sections []NoteSection
+	first := true
+	forEachTextLine(lines, func(i int, line string) {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		m := headingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			first = false
+			return
+		}
+		if first && strings.TrimSpace(m[2]) == strings.TrimSpace(title) {
+			first = false
+			return
+		}
+		first = false
+		sections = append(sections, NoteSection{Title: m[2], Level: len(m[1]), Start: i, End: len(lines)})
+	})
+
+	for i := range sections {
+		for _, next := range sections[i+1:] {
+			if next.Level <= sections[i].Level {
+				sections[i].End = next.Start
+				break
+			}
+		}
+	}
+	return sections
+}
+
+// Lines of a section below its heading, without surrounding blank lines
+func (s NoteSection) Content(body string) string {
+	lines := strings.Split(body, "\n")
+	return strings.TrimSpace(strings.Join(lines[s.Start+1:s.End], "\n"))
+}
+
+// Check whether a section lies inside another
+func (s NoteSection) Within(other NoteSection) bool {
+	return s.Start > other.Start && s.End <= other.End
+}
+
+// Replace sections of a body (in document order, not overlapping) with other text
+func ReplaceSections(body string, sections []NoteSection, replacements []string) string {
+	lines := strings.Split(body, "\n")
+	for i := len(sections) - 1; i >= 0; i-- {
+		section := sections[i]
+		replaced := append([]string{}, lines[:section.Start]...)
+		replaced = append(replaced, replacements[i])
+		// Replacements of adjacent sections form a single block
+		if i == len(sections)-1 || sections[i+1].Start != section.End {
+			replaced = append(replaced, "")
+		}
+		lines = append(replaced, lines[section.End:]...)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Anchor of a heading as generated by Markdown renderers (`Set up DNS!` → `set-up-dns`)
+func HeadingAnchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// Point the links to a section of a note (`[text](NoteID.md#heading)`,
+// `[[NoteID#Heading]]`) at the note the section was moved to
+func RedirectSectionLinks(content string, from Zettel, heading string, to Zettel) (string, []LineChange) {
+	anchor := HeadingAnchor(heading)
+	matches := func(a string) bool {
+		return strings.EqualFold(strings.TrimSpace(a), heading) || HeadingAnchor(a) == anchor
+	}
+
+	return rewriteBodyLinks(content, func(link string) string {
+		if strings.HasPrefix(link, "[[") {
+			target, linkAnchor, label, hasAnchor, hasLabel := splitWikiLink(link)
+			if !hasAnchor || !wikiTargets(target, from) || !matches(linkAnchor) {
+				return link
+			}
+			if strings.TrimSuffix(strings.TrimSpace(target), ".md") == from.NoteID {
+				target = to.NoteID
+			} else {
+				target = to.Title
+			}
+			return joinWikiLink(target, "", label, false, hasLabel)
+		}
+
+		text, dest, rest, ok := splitMarkdownLink(link)
+		if !ok || path.Base(dest) != from.NoteID+".md" || !strings.HasPrefix(rest, "#") {
+			return link
+		}
+		linkAnchor, rest, _ := strings.Cut(rest[1:], " ")
+		if !matches(linkAnchor) {
+			return link
+		}
+		if rest != "" {
+			rest = " " + rest
+		}
+		dest = strings.TrimSuffix(dest, from.NoteID+".md") + to.NoteID + ".md"
+		return "[" + text + "](" + dest + rest + ")"
+	})
+}
@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var splitBody = strings.Join([]string{
+	"## Note",
+	"",
+	"Intro",
+	"## Setup",
+	"text",
+	"### Details",
+	"more",
+	"```",
+	"# not a heading",
+	"```",
+	"## Setup",
+	"again",
+	"# Top ##",
+	"end",
+}, "\n")
+
+func TestNoteSections(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		title string
+		want  []NoteSection
+	}{
+		{
+			"nested and duplicate headings",
+			splitBody,
+			"Note",
+			[]NoteSection{
+				{Title: "Setup", Level: 2, Start: 3, End: 10},
+				{Title: "Details", Level: 3, Start: 5, End: 10},
+				{Title: "Setup", Level: 2, Start: 10, End: 12},
+				{Title: "Top", Level: 1, Start: 12, End: 14},
+			},
+		},
+		{
+			"title heading kept for another note",
+			splitBody,
+			"Other",
+			[]NoteSection{
+				{Title: "Note", Level: 2, Start: 0, End: 3},
+				{Title: "Setup", Level: 2, Start: 3, End: 10},
+				{Title: "Details", Level: 3, Start: 5, End: 10},
+				{Title: "Setup", Level: 2, Start: 10, End: 12},
+				{Title: "Top", Level: 1, Start: 12, End: 14},
+			},
+		},
+		{
+			// A leading `---` is a horizontal rule, so the heading after it is a section
+			"leading rule",
+			"---\n## Note\ntext",
+			"Note",
+			[]NoteSection{{Title: "Note", Level: 2, Start: 1, End: 3}},
+		},
+		{"no headings", "Just text\n#hashtag", "Note", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoteSections(tt.body, tt.title); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoteSectionContent(t *testing.T) {
+	sections := NoteSections(splitBody, "Note")
+	setup, details, again := sections[0], sections[1], sections[2]
+
+	if got, want := setup.Content(splitBody), "text\n### Details\nmore\n```\n# not a heading\n```"; got != want {
+		t.Errorf("Content = %q, want %q", got, want)
+	}
+	if got := again.Content(splitBody); got != "again" {
+		t.Errorf("Content = %q, want %q", got, "again")
+	}
+	if !details.Within(setup) || again.Within(setup) || setup.Within(setup) {
+		t.Error("Within should only hold for sections nested in another")
+	}
+}
+
+func TestReplaceSections(t *testing.T) {
+	sections := NoteSections(splitBody, "Note")
+
+	tests := []struct {
+		name         string
+		sections     []NoteSection
+		replacements []string
+		want         string
+	}{
+		{
+			"single section",
+			[]NoteSection{sections[3]},
+			[]string{"[[Top]]"},
+			"## Note\n\nIntro\n## Setup\ntext\n### Details\nmore\n```\n# not a heading\n```\n## Setup\nagain\n[[Top]]",
+		},
+		{
+			"adjacent sections form one block",
+			[]NoteSection{sections[1], sections[2]},
+			[]string{"[[Details]]", "[[Setup]]"},
+			"## Note\n\nIntro\n## Setup\ntext\n[[Details]]\n[[Setup]]\n\n# Top ##\nend",
+		},
+		{
+			"separate sections",
+			[]NoteSection{sections[0], sections[3]},
+			[]string{"[[Setup]]", "[[Top]]"},
+			"## Note\n\nIntro\n[[Setup]]\n\n## Setup\nagain\n[[Top]]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReplaceSections(splitBody, tt.sections, tt.replacements); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadingAnchor(t *testing.T) {
+	tests := map[string]string{
+		"Set up DNS!":       "set-up-dns",
+		"  API_v2 - notes ": "api_v2---notes",
+		"日本語 見出し":           "日本語-見出し",
+		"":                  "",
+	}
+	for heading, want := range tests {
+		if got := HeadingAnchor(heading); got != want {
+			t.Errorf("HeadingAnchor(%q) = %q, want %q", heading, got, want)
+		}
+	}
+}
+
+func TestRedirectSectionLinks(t *testing.T) {
+	content := strings.Join([]string{
+		"---",
+		"title: Links",
+		"---",
+		"[dns](20250301090000.md#set-up-dns) [x](20250301090000.md#other) [all](20250301090000.md)",
+		"[[Alpha#Set up DNS]] [[20250301090000#set-up-dns|label]] [[Alpha]] [[Beta#Set up DNS]]",
+		`[t](notes/20250301090000.md#set-up-dns "T")`,
+	}, "\n")
+	want := strings.Join([]string{
+		"---",
+		"title: Links",
+		"---",
+		"[dns](20250303090000.md) [x](20250301090000.md#other) [all](20250301090000.md)",
+		"[[Gamma]] [[20250303090000|label]] [[Alpha]] [[Beta#Set up DNS]]",
+		`[t](notes/20250303090000.md "T")`,
+	}, "\n")
+
+	got, changes := RedirectSectionLinks(content, alphaNote, "Set up DNS", gammaNote)
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if len(changes) != 3 {
+		t.Errorf("got %d changed lines, want 3", len(changes))
+	}
+}
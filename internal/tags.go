@@ -0,0 +1,298 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// How several tag filters combine
+const (
+	TagModeAny = "any" // A note needs one of the tags
+	TagModeAll = "all" // A note needs every tag
+)
+
+var TagModes = []string{TagModeAny, TagModeAll}
+
+// Separator of hierarchical tags (`area/infra/k8s`)
+const tagSeparator = "/"
+
+// Suffix of a filter matching a tag and everything below it (`lang/*`),
+// kept for filters written before sub-tags were matched by default
+const tagDescendants = "/*"
+
+// Prefix of a filter matching the tag alone, without its sub-tags (`=lang`)
+const tagExact = "="
+
+// Check whether a tag mode is supported
+func ValidateTagMode(mode string) error {
+	if !containsString(TagModes, mode) {
+		return fmt.Errorf("invalid tag mode %q: must be one of '%s'", mode, strings.Join(TagModes, "', '"))
+	}
+	return nil
+}
+
+// Normalize a tag for comparison
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// Normalized hierarchical path of a tag. The `project:` prefix is a namespace
+// like any other, so `project:Alpha` is the tag `Alpha` below `project`.
+func tagPath(tag string) string {
+	tag = normalizeTag(tag)
+	if rest, ok := strings.CutPrefix(tag, projectTagPrefix); ok {
+		return "project" + tagSeparator + rest
+	}
+	return tag
+}
+
+// Segments of a tag as written (`project:Alpha` → `project`, `Alpha`)
+func tagSegments(tag string) []string {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(strings.ToLower(tag), projectTagPrefix) {
+		return append([]string{tag[:len(projectTagPrefix)-1]}, strings.Split(tag[len(projectTagPrefix):], tagSeparator)...)
+	}
+	return strings.Split(tag, tagSeparator)
+}
+
+// Check whether a tag is below another (`area/infra/k8s` is below `area/infra`)
+func isSubTag(tag, parent string) bool {
+	return strings.HasPrefix(tagPath(tag), tagPath(parent)+tagSeparator)
+}
+
+// Part of a tag below a parent tag (`infra/k8s` for `area/infra/k8s` below `area`)
+func SubTag(tag, parent string) (string, bool) {
+	if !isSubTag(tag, parent) {
+		return "", false
+	}
+	return strings.TrimSpace(tag)[len(tagPath(parent))+len(tagSeparator):], true
+}
+
+// Check whether a note tag matches a tag filter (case-insensitive). A filter
+// matches the tag and the tags below it (`area/infra` matches `area/infra/k8s`);
+// a filter starting with `=` matches the tag alone.
+func TagMatches(tag, filter string) bool {
+	filter = strings.TrimSpace(filter)
+	if exact, ok := strings.CutPrefix(filter, tagExact); ok {
+		return tagPath(tag) == tagPath(exact)
+	}
+	filter = strings.TrimSuffix(filter, tagDescendants)
+	return tagPath(tag) == tagPath(filter) || isSubTag(tag, filter)
+}
+
+// Check whether any of the tags matches the filter
+func HasTag(tags []string, filter string) bool {
+	for _, tag := range tags {
+		if TagMatches(tag, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check whether the tags pass the filters: one of them (`any`) or all of them (`all`).
+// No filters always match.
+func MatchTags(tags, filters []string, mode string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		matched := HasTag(tags, filter)
+		if matched && mode != TagModeAll {
+			return true
+		}
+		if !matched && mode == TagModeAll {
+			return false
+		}
+	}
+	return mode == TagModeAll
+}
+
+// Usage of a tag across the notes
+type TagStat struct {
+	Tag      string
+	Count    int
+	LastUsed string // Latest update of a note carrying the tag
+}
+
+// Count the tags of the notes that are not in the trash, most used first.
+// Tags differing only in case are counted together under their first spelling.
+func CollectTags(zettels []Zettel) []TagStat {
+	stats := make(map[string]*TagStat)
+	var order []string
+	for _, z := range zettels {
+		if z.Deleted {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, tag := range z.Tags {
+			key := normalizeTag(tag)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			stat, ok := stats[key]
+			if !ok {
+				stat = &TagStat{Tag: strings.TrimSpace(tag)}
+				stats[key] = stat
+				order = append(order, key)
+			}
+			stat.Count++
+			used := z.UpdatedAt
+			if used == "" {
+				used = z.CreatedAt
+			}
+			if used > stat.LastUsed {
+				stat.LastUsed = used
+			}
+		}
+	}
+
+	result := make([]TagStat, 0, len(order))
+	for _, key := range order {
+		result = append(result, *stats[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return strings.ToLower(result[i].Tag) < strings.ToLower(result[j].Tag)
+	})
+	return result
+}
+
+// Check whether a tag (itself, not only below it) is used by any note
+func TagInUse(zettels []Zettel, tag string) bool {
+	for _, z := range zettels {
+		if HasTag(z.Tags, tagExact+tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check that a tag can be renamed or merged into another: a tag below the old
+// one would be replaced again with each of its own sub-tags (`lang` into
+// `lang/go` turns `lang/go` into `lang/go/go`)
+func ValidateTagMove(oldTag, newTag string) error {
+	if isSubTag(newTag, oldTag) {
+		return fmt.Errorf("cannot move tag %q below itself (%q)", strings.TrimSpace(oldTag), strings.TrimSpace(newTag))
+	}
+	return nil
+}
+
+// Replace a tag, and the tags below it, with another (`lang` → `language`
+// also turns `lang/go` into `language/go`). Duplicates left by the
+// replacement are dropped.
+func ReplaceTag(tags []string, oldTag, newTag string) ([]string, bool) {
+	oldPath := tagPath(oldTag)
+	newTag = strings.TrimSpace(newTag)
+
+	changed := false
+	replaced := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if tagPath(tag) == oldPath {
+			tag = newTag
+			changed = true
+		} else if isSubTag(tag, oldTag) {
+			// Keep the separator as written (`project:` or `/`)
+			tag = newTag + strings.TrimSpace(tag)[len(oldPath):]
+			changed = true
+		}
+		if !seen[normalizeTag(tag)] {
+			seen[normalizeTag(tag)] = true
+			replaced = append(replaced, tag)
+		}
+	}
+	return replaced, changed
+}
+
+// Word a note's front matter must contain to match a tag filter: the last
+// segment of the filter, used by ripgrep to preselect notes
+func TagFilterTerm(filter string) string {
+	filter = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(filter), tagExact), tagDescendants)
+	segments := tagSegments(filter)
+	return segments[len(segments)-1]
+}
+
+// Node of the tag hierarchy
+type TagNode struct {
+	Name     string // Segment as first written
+	Path     string // Full tag as first written
+	Count    int    // Notes carrying the tag itself
+	Total    int    // Notes carrying the tag or a tag below it
+	LastUsed string
+	Children []*TagNode
+}
+
+// Build the tag hierarchy of the notes that are not in the trash, sorted by name.
+// Parents missing as tags of their own are added with a count of zero.
+func TagTree(zettels []Zettel) []*TagNode {
+	nodes := make(map[string]*TagNode)
+	var roots []*TagNode
+	get := func(segments []string, depth int) *TagNode {
+		path := strings.Join(segments[:depth], tagSeparator)
+		key := tagPath(path)
+		if depth >= 2 && strings.EqualFold(segments[0]+":", projectTagPrefix) {
+			// Written back with the namespace prefix (`project:Alpha`)
+			path = segments[0] + ":" + strings.Join(segments[1:depth], tagSeparator)
+		}
+		if node, ok := nodes[key]; ok {
+			return node
+		}
+		node := &TagNode{Name: segments[depth-1], Path: path}
+		nodes[key] = node
+		if depth == 1 {
+			roots = append(roots, node)
+		} else {
+			parent := nodes[tagPath(strings.Join(segments[:depth-1], tagSeparator))]
+			parent.Children = append(parent.Children, node)
+		}
+		return node
+	}
+
+	for _, z := range zettels {
+		if z.Deleted {
+			continue
+		}
+		used := z.UpdatedAt
+		if used == "" {
+			used = z.CreatedAt
+		}
+		counted := make(map[*TagNode]bool)
+		own := make(map[*TagNode]bool)
+		for _, tag := range z.Tags {
+			if normalizeTag(tag) == "" {
+				continue
+			}
+			segments := tagSegments(tag)
+			for depth := 1; depth <= len(segments); depth++ {
+				node := get(segments, depth)
+				if !counted[node] {
+					counted[node] = true
+					node.Total++
+					if used > node.LastUsed {
+						node.LastUsed = used
+					}
+				}
+				if depth == len(segments) && !own[node] {
+					own[node] = true
+					node.Count++
+				}
+			}
+		}
+	}
+
+	var sortNodes func(list []*TagNode)
+	sortNodes = func(list []*TagNode) {
+		sort.SliceStable(list, func(i, j int) bool { return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name) })
+		for _, node := range list {
+			sortNodes(node.Children)
+		}
+	}
+	sortNodes(roots)
+	return roots
+}
@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTagMatches(t *testing.T) {
+	tests := []struct {
+		tag, filter string
+		want        bool
+	}{
+		{"go", "go", true},
+		{"Go", "go", true},
+		{"lang/go", "lang", true},
+		{"lang", "lang/go", false},
+		{"language", "lang", false},
+		{"lang/go", "lang/*", true},
+		{"lang", "lang/*", true},
+		{"lang", "=lang", true},
+		{"Lang", " =lang ", true},
+		{"lang/go", "=lang", false},
+		{"lang/go", "=lang/go", true},
+		{"project:Alpha", "project", true},
+		{"project:alpha", "project:Alpha", true},
+		{"project:alpha/api", "project:alpha", true},
+		{"project:alpha", "=project", false},
+	}
+	for _, tt := range tests {
+		if got := TagMatches(tt.tag, tt.filter); got != tt.want {
+			t.Errorf("TagMatches(%q, %q) = %v, want %v", tt.tag, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTags(t *testing.T) {
+	tags := []string{"project:alpha", "code/review"}
+
+	tests := []struct {
+		filters []string
+		mode    string
+		want    bool
+	}{
+		{nil, TagModeAny, true},
+		{nil, TagModeAll, true},
+		{[]string{"code"}, TagModeAny, true},
+		{[]string{"=code"}, TagModeAny, false},
+		{[]string{"travel", "code"}, TagModeAny, true},
+		{[]string{"travel", "writing"}, TagModeAny, false},
+		{[]string{"project:alpha", "code"}, TagModeAll, true},
+		{[]string{"project:alpha", "=code"}, TagModeAll, false},
+		{[]string{"project:alpha", "writing"}, TagModeAll, false},
+	}
+	for _, tt := range tests {
+		if got := MatchTags(tags, tt.filters, tt.mode); got != tt.want {
+			t.Errorf("MatchTags(%q, %s) = %v, want %v", tt.filters, tt.mode, got, tt.want)
+		}
+	}
+
+	if err := ValidateTagMode("some"); err == nil {
+		t.Error("ValidateTagMode should reject an unknown mode")
+	}
+}
+
+func TestReplaceTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		tags           []string
+		oldTag, newTag string
+		want           []string
+		changed        bool
+	}{
+		{"no match", []string{"go", "language"}, "lang", "code", []string{"go", "language"}, false},
+		{"renamed", []string{"go", "Lang"}, "lang", "language", []string{"go", "language"}, true},
+		{"sub-tags follow", []string{"lang", "lang/go", "Lang/Rust"}, "lang", "language", []string{"language", "language/go", "language/Rust"}, true},
+		{"sub-tag only", []string{"lang/go/generics"}, "lang/go", "golang", []string{"golang/generics"}, true},
+		{"merged duplicates", []string{"old", "new", "old/x", "new/x"}, "old", "new", []string{"new", "new/x"}, true},
+		{"project namespace", []string{"project:alpha", "project:alpha/api"}, "project:alpha", "project:beta", []string{"project:beta", "project:beta/api"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := ReplaceTag(tt.tags, tt.oldTag, tt.newTag)
+			if !reflect.DeepEqual(got, tt.want) || changed != tt.changed {
+				t.Errorf("got %q, %v; want %q, %v", got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
+
+func TestValidateTagMove(t *testing.T) {
+	tests := []struct {
+		oldTag, newTag string
+		valid          bool
+	}{
+		{"lang", "language", true},
+		{"lang/go", "lang", true},
+		{"lang", "code/lang", true},
+		{"lang", "lang/go", false},
+		{"Lang", "lang/Go/x", false},
+		{"project:alpha", "project:alpha/old", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateTagMove(tt.oldTag, tt.newTag); (err == nil) != tt.valid {
+			t.Errorf("ValidateTagMove(%q, %q) = %v, want valid %v", tt.oldTag, tt.newTag, err, tt.valid)
+		}
+	}
+}
+
+func TestSubTag(t *testing.T) {
+	tests := []struct {
+		tag, parent string
+		want        string
+		ok          bool
+	}{
+		{"area/infra/k8s", "area", "infra/k8s", true},
+		{"Area/Infra", "area", "Infra", true},
+		{"Project:Alpha/api", "project:alpha", "api", true},
+		{"area", "area", "", false},
+		{"areas/x", "area", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := SubTag(tt.tag, tt.parent); got != tt.want || ok != tt.ok {
+			t.Errorf("SubTag(%q, %q) = %q, %v; want %q, %v", tt.tag, tt.parent, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTagFilterTerm(t *testing.T) {
+	tests := map[string]string{
+		"go":            "go",
+		"=lang/go":      "go",
+		"lang/*":        "lang",
+		"project:Alpha": "Alpha",
+	}
+	for filter, want := range tests {
+		if got := TagFilterTerm(filter); got != want {
+			t.Errorf("TagFilterTerm(%q) = %q, want %q", filter, got, want)
+		}
+	}
+}
+
+func TestCollectTags(t *testing.T) {
+	zettels := []Zettel{
+		{Tags: []string{"go", "Rust"}, UpdatedAt: "2025-03-02 09:00:00"},
+		{Tags: []string{"Go", " "}, UpdatedAt: "2025-03-05 09:00:00"},
+		{Tags: []string{"rust", "rust"}, CreatedAt: "2025-03-01 09:00:00"},
+		{Tags: []string{"draft"}, UpdatedAt: "2025-03-09 09:00:00"},
+		{Tags: []string{"rust"}, UpdatedAt: "2025-03-10 09:00:00", Deleted: true},
+	}
+	want := []TagStat{
+		{Tag: "go", Count: 2, LastUsed: "2025-03-05 09:00:00"},
+		{Tag: "Rust", Count: 2, LastUsed: "2025-03-02 09:00:00"},
+		{Tag: "draft", Count: 1, LastUsed: "2025-03-09 09:00:00"},
+	}
+	if got := CollectTags(zettels); !reflect.DeepEqual(got, want) {
+		t.Errorf("CollectTags = %+v, want %+v", got, want)
+	}
+
+	if !TagInUse(zettels, "GO") || TagInUse(zettels, "go/generics") {
+		t.Error("TagInUse should only find tags carried by a note")
+	}
+	if TagInUse([]Zettel{{Tags: []string{"lang/go"}}}, "lang") {
+		t.Error("TagInUse should not count a sub-tag as the tag itself")
+	}
+}
+
+func TestTagTree(t *testing.T) {
+	zettels := []Zettel{
+		{Tags: []string{"lang/go", "lang/rust"}},
+		{Tags: []string{"lang/go/generics", "project:Alpha"}},
+		{Tags: []string{"x"}, Deleted: true},
+	}
+
+	var walk func(nodes []*TagNode) []string
+	walk = func(nodes []*TagNode) []string {
+		var lines []string
+		for _, node := range nodes {
+			lines = append(lines, fmt.Sprintf("%s %d/%d", node.Path, node.Count, node.Total))
+			lines = append(lines, walk(node.Children)...)
+		}
+		return lines
+	}
+	want := []string{
+		"lang 0/2",
+		"lang/go 1/2",
+		"lang/go/generics 1/1",
+		"lang/rust 1/1",
+		"project 0/1",
+		"project:Alpha 1/1",
+	}
+	if got := walk(TagTree(zettels)); !reflect.DeepEqual(got, want) {
+		t.Errorf("TagTree = %q, want %q", got, want)
+	}
+}
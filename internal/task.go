@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts used for task dates in front matter and zettel.json
+const (
+	DueDateLayout   = "2006-01-02"
+	TimestampLayout = "2006-01-02 15:04:05"
+)
+
+// Default task workflow used when `task.statuses` is not configured
+var DefaultTaskStatuses = []string{"Not started", "In progress", "Waiting", "On hold", "Done"}
+
+// Task priorities, highest first
+var TaskPriorities = []string{"high", "medium", "low"}
+
+// Sort fields accepted by `zk task list --sort`
+var TaskSortFields = []string{"due", "priority", "status"}
+
+// Get the configured task workflow (falls back to the default one)
+func (c Config) TaskStatuses() []string {
+	if len(c.Task.Statuses) > 0 {
+		return c.Task.Statuses
+	}
+	return DefaultTaskStatuses
+}
+
+// Get the status that marks a task as completed
+func (c Config) TaskDoneStatus() string {
+	if c.Task.DoneStatus != "" {
+		return c.Task.DoneStatus
+	}
+	statuses := c.TaskStatuses()
+	return statuses[len(statuses)-1]
+}
+
+func normalizeStatusKey(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	status = strings.ReplaceAll(status, "_", " ")
+	status = strings.ReplaceAll(status, "-", " ")
+	return strings.Join(strings.Fields(status), " ")
+}
+
+// Validate a task status against the workflow and return its canonical spelling
+func NormalizeTaskStatus(status string, statuses []string) (string, error) {
+	key := normalizeStatusKey(status)
+	for _, s := range statuses {
+		if normalizeStatusKey(s) == key {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid task status %q: must be one of %s", status, quoteList(statuses))
+}
+
+// Validate a task priority (empty means no priority)
+func NormalizeTaskPriority(priority string) (string, error) {
+	p := strings.ToLower(strings.TrimSpace(priority))
+	if p == "" {
+		return "", nil
+	}
+	for _, valid := range TaskPriorities {
+		if p == valid || p == valid[:1] {
+			return valid, nil
+		}
+	}
+	return "", fmt.Errorf("invalid task priority %q: must be one of %s", priority, quoteList(TaskPriorities))
+}
+
+// Parse a due date given as YYYY-MM-DD, `today`, `tomorrow`, `+Nd`/`+Nw` (or `-Nd`/`-Nw` for past dates)
+func ParseDueDate(due string, now time.Time) (string, error) {
+	d := strings.ToLower(strings.TrimSpace(due))
+	if d == "" {
+		return "", nil
+	}
+
+	switch d {
+	case "today":
+		return now.Format(DueDateLayout), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format(DueDateLayout), nil
+	}
+
+	if (strings.HasPrefix(d, "+") || strings.HasPrefix(d, "-")) && len(d) > 2 {
+		n, err := strconv.Atoi(d[1 : len(d)-1])
+		if err == nil && n >= 0 {
+			if d[0] == '-' {
+				n = -n
+			}
+			switch d[len(d)-1] {
+			case 'd':
+				return now.AddDate(0, 0, n).Format(DueDateLayout), nil
+			case 'w':
+				return now.AddDate(0, 0, 7*n).Format(DueDateLayout), nil
+			}
+		}
+	}
+
+	t, err := time.ParseInLocation(DueDateLayout, d, now.Location())
+	if err != nil {
+		return "", fmt.Errorf("invalid due date %q: use YYYY-MM-DD, today, tomorrow, +Nd or +Nw", due)
+	}
+	return t.Format(DueDateLayout), nil
+}
+
+// Apply a status change to front matter, keeping `completed_at` in step with the workflow
+func SetTaskStatus(frontMatter *FrontMatter, status, doneStatus string, now time.Time) {
+	if frontMatter.TaskStatus == status {
+		return
+	}
+	frontMatter.TaskStatus = status
+	frontMatter.UpdatedAt = now.Format(TimestampLayout)
+	if status == doneStatus {
+		frontMatter.CompletedAt = now.Format(TimestampLayout)
+	} else {
+		frontMatter.CompletedAt = ""
+	}
+}
+
+func indexOf(slice []string, item string) int {
+	for i, val := range slice {
+		if strings.EqualFold(val, item) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Rank a value within an ordered list; unknown and empty values sort last
+func rankOf(order []string, item string) int {
+	if i := indexOf(order, item); i >= 0 {
+		return i
+	}
+	return len(order)
+}
+
+// Sort tasks in place by `due`, `priority` or `status`
+func SortTasks(tasks []Zettel, field string, statuses []string) error {
+	var less func(a, b Zettel) bool
+
+	switch strings.ToLower(field) {
+	case "":
+		return nil
+	case "due":
+		less = func(a, b Zettel) bool {
+			if a.Due == "" || b.Due == "" {
+				return a.Due != "" && b.Due == ""
+			}
+			return a.Due < b.Due
+		}
+	case "priority":
+		less = func(a, b Zettel) bool {
+			return rankOf(TaskPriorities, a.Priority) < rankOf(TaskPriorities, b.Priority)
+		}
+	case "status":
+		less = func(a, b Zettel) bool {
+			return rankOf(statuses, a.TaskStatus) < rankOf(statuses, b.TaskStatus)
+		}
+	default:
+		return fmt.Errorf("invalid sort field %q: must be one of %s", field, quoteList(TaskSortFields))
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return less(tasks[i], tasks[j])
+	})
+	return nil
+}
+
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("'%s'", item)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Column of a task board
+type TaskColumn struct {
+	Status string
+	Tasks  []Zettel
+}
+
+// Group tasks into board columns following the workflow order
+func GroupTasksByStatus(tasks []Zettel, statuses []string) []TaskColumn {
+	columns := make([]TaskColumn, len(statuses))
+	for i, status := range statuses {
+		columns[i] = TaskColumn{Status: status}
+	}
+
+	for _, task := range tasks {
+		i := indexOf(statuses, task.TaskStatus)
+		if i < 0 {
+			// Keep tasks with statuses outside the workflow visible
+			columns = append(columns, TaskColumn{Status: task.TaskStatus})
+			statuses = append(statuses[:len(statuses):len(statuses)], task.TaskStatus)
+			i = len(columns) - 1
+		}
+		columns[i].Tasks = append(columns[i].Tasks, task)
+	}
+	return columns
+}
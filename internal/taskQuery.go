@@ -0,0 +1,113 @@
+package internal
+
+import "time"
+
+// Filters accepted by `zk task list`
+type TaskQuery struct {
+	Statuses  []string
+	Project   string
+	Tags      []string
+	TagMode   string
+	Overdue   bool
+	DueBefore string
+	Sort      string
+	Trash     bool
+	Archive   bool
+}
+
+// Check whether a task is past its due date and not yet done
+func IsOverdue(task Zettel, doneStatus string, now time.Time) bool {
+	if task.Due == "" || task.TaskStatus == doneStatus {
+		return false
+	}
+	return task.Due < now.Format(DueDateLayout)
+}
+
+// Resolve relative values (statuses, due dates) against the configuration
+func (q TaskQuery) normalize(config Config, now time.Time) (TaskQuery, error) {
+	normalized := q
+	normalized.Statuses = nil
+	for _, status := range q.Statuses {
+		s, err := NormalizeTaskStatus(status, config.TaskStatuses())
+		if err != nil {
+			return TaskQuery{}, err
+		}
+		normalized.Statuses = append(normalized.Statuses, s)
+	}
+
+	if normalized.TagMode == "" {
+		normalized.TagMode = TagModeAny
+	}
+	if err := ValidateTagMode(normalized.TagMode); err != nil {
+		return TaskQuery{}, err
+	}
+
+	dueBefore, err := ParseDueDate(q.DueBefore, now)
+	if err != nil {
+		return TaskQuery{}, err
+	}
+	normalized.DueBefore = dueBefore
+
+	return normalized, nil
+}
+
+// Check whether a task matches the query (expects a normalized query)
+func (q TaskQuery) match(task Zettel, doneStatus string, now time.Time) bool {
+	if task.NoteType != "task" {
+		return false
+	}
+
+	// Trash and archive filters
+	if q.Trash != task.Deleted {
+		return false
+	}
+	if q.Archive && !task.Archived {
+		return false
+	}
+
+	// Filter by status
+	if len(q.Statuses) > 0 && indexOf(q.Statuses, task.TaskStatus) < 0 {
+		return false
+	}
+
+	// Filter by project
+	if q.Project != "" && !InProject(task, q.Project) {
+		return false
+	}
+
+	// Filter by tags
+	if !MatchTags(task.Tags, q.Tags, q.TagMode) {
+		return false
+	}
+
+	// Filter by due date
+	if q.Overdue && !IsOverdue(task, doneStatus, now) {
+		return false
+	}
+	if q.DueBefore != "" && (task.Due == "" || task.Due >= q.DueBefore) {
+		return false
+	}
+
+	return true
+}
+
+// Filter and sort tasks from the index
+func QueryTasks(zettels []Zettel, q TaskQuery, config Config, now time.Time) ([]Zettel, error) {
+	normalized, err := q.normalize(config, now)
+	if err != nil {
+		return nil, err
+	}
+
+	doneStatus := config.TaskDoneStatus()
+	tasks := []Zettel{}
+	for _, zettel := range zettels {
+		if normalized.match(zettel, doneStatus, now) {
+			tasks = append(tasks, zettel)
+		}
+	}
+
+	if err := SortTasks(tasks, normalized.Sort, config.TaskStatuses()); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tasks of testdata/zettel.json, queried on 2025-03-10
+func loadTaskFixture(t *testing.T) ([]Zettel, Config, time.Time) {
+	t.Helper()
+	config := Config{ZettelJson: "testdata/zettel.json"}
+	zettels, err := LoadJson(config)
+	if err != nil {
+		t.Fatalf("loading fixture: %v", err)
+	}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+	return zettels, config, now
+}
+
+func taskTitles(tasks []Zettel) []string {
+	titles := []string{}
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func TestQueryTasks(t *testing.T) {
+	zettels, config, now := loadTaskFixture(t)
+
+	tests := []struct {
+		name  string
+		query TaskQuery
+		want  []string
+	}{
+		{"all", TaskQuery{}, []string{"Write report", "Review pull request", "Plan trip", "Fix login bug", "Ship release"}},
+		{"status", TaskQuery{Statuses: []string{"in-progress"}}, []string{"Review pull request"}},
+		{"statuses", TaskQuery{Statuses: []string{"not_started", "Waiting"}}, []string{"Write report", "Fix login bug"}},
+		{"project", TaskQuery{Project: "alpha"}, []string{"Write report", "Review pull request"}},
+		{"tag", TaskQuery{Tags: []string{"code"}}, []string{"Review pull request", "Fix login bug"}},
+		{"exact tag", TaskQuery{Tags: []string{"=code"}}, []string{"Fix login bug"}},
+		{"tags any", TaskQuery{Tags: []string{"travel", "writing"}}, []string{"Write report", "Plan trip"}},
+		{"tags all", TaskQuery{Tags: []string{"project:alpha", "writing"}, TagMode: TagModeAll}, []string{"Write report"}},
+		{"overdue", TaskQuery{Overdue: true}, []string{"Write report"}},
+		{"due before date", TaskQuery{DueBefore: "2025-03-05"}, []string{"Plan trip", "Ship release"}},
+		{"due before relative", TaskQuery{DueBefore: "+3d"}, []string{"Write report", "Review pull request", "Plan trip", "Ship release"}},
+		{"trash", TaskQuery{Trash: true}, []string{"Old draft"}},
+		{"archive", TaskQuery{Archive: true}, []string{"Ship release"}},
+		{"project and overdue", TaskQuery{Project: "beta", Overdue: true}, []string{}},
+		{"tag and status", TaskQuery{Tags: []string{"code"}, Statuses: []string{"waiting"}}, []string{"Fix login bug"}},
+		{"project, due before and sort", TaskQuery{Project: "alpha", DueBefore: "2025-03-31", Sort: "priority"}, []string{"Write report", "Review pull request"}},
+		{"no match", TaskQuery{Project: "alpha", Statuses: []string{"Done"}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := QueryTasks(zettels, tt.query, config, now)
+			if err != nil {
+				t.Fatalf("QueryTasks: %v", err)
+			}
+			if got := taskTitles(tasks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryTasksSort(t *testing.T) {
+	zettels, config, now := loadTaskFixture(t)
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"due", []string{"Ship release", "Plan trip", "Write report", "Review pull request", "Fix login bug"}},
+		{"priority", []string{"Write report", "Plan trip", "Fix login bug", "Review pull request", "Ship release"}},
+		{"status", []string{"Write report", "Review pull request", "Fix login bug", "Plan trip", "Ship release"}},
+		{"Due", []string{"Ship release", "Plan trip", "Write report", "Review pull request", "Fix login bug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			tasks, err := QueryTasks(zettels, TaskQuery{Sort: tt.sort}, config, now)
+			if err != nil {
+				t.Fatalf("QueryTasks: %v", err)
+			}
+			if got := taskTitles(tasks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryTasksConfiguredWorkflow(t *testing.T) {
+	zettels, config, now := loadTaskFixture(t)
+	config.Task.Statuses = []string{"Waiting", "In progress", "Not started", "Done"}
+
+	tasks, err := QueryTasks(zettels, TaskQuery{Sort: "status"}, config, now)
+	if err != nil {
+		t.Fatalf("QueryTasks: %v", err)
+	}
+	want := []string{"Fix login bug", "Review pull request", "Write report", "Plan trip", "Ship release"}
+	if got := taskTitles(tasks); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := QueryTasks(zettels, TaskQuery{Statuses: []string{"On hold"}}, config, now); err == nil {
+		t.Error("expected a status outside the configured workflow to be rejected")
+	}
+}
+
+func TestQueryTasksInvalid(t *testing.T) {
+	zettels, config, now := loadTaskFixture(t)
+
+	tests := []struct {
+		name  string
+		query TaskQuery
+		want  string
+	}{
+		{"status", TaskQuery{Statuses: []string{"Started"}}, "invalid task status"},
+		{"date", TaskQuery{DueBefore: "2025-13-01"}, "invalid due date"},
+		{"relative date", TaskQuery{DueBefore: "+3m"}, "invalid due date"},
+		{"sort", TaskQuery{Sort: "title"}, "invalid sort field"},
+		{"tag mode", TaskQuery{Tags: []string{"code"}, TagMode: "some"}, "tag mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := QueryTasks(zettels, tt.query, config, now)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
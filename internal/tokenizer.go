@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"os/exec"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer names stored in the search index cache
+const (
+	TokenizerMeCab  = "mecab"
+	TokenizerSimple = "simple"
+)
+
+// Pick the tokenizer: MeCab (as used for linking) when installed, otherwise the built-in one
+func DetectTokenizer() string {
+	if _, err := exec.LookPath("mecab"); err == nil {
+		return TokenizerMeCab
+	}
+	return TokenizerSimple
+}
+
+// Split text into lower-cased search terms
+func Tokenize(text, tokenizer string) []string {
+	if tokenizer == TokenizerMeCab {
+		keywords, err := ExtractKeywordsMeCab(text)
+		if err == nil {
+			terms := make([]string, 0, len(keywords))
+			for _, keyword := range keywords {
+				if term := strings.ToLower(strings.TrimSpace(keyword)); term != "" {
+					terms = append(terms, term)
+				}
+			}
+			return terms
+		}
+	}
+	return simpleTokenize(text)
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// Built-in tokenizer: words for alphabetic scripts, character bigrams for CJK text
+func simpleTokenize(text string) []string {
+	var terms []string
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			terms = append(terms, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			terms = append(terms, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			terms = append(terms, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return terms
+}
@@ -1,16 +1,39 @@
 package internal
 
 type Zettel struct {
-	ID         string   `json:"id"`
-	NoteID     string   `json:"note_id"`
-	Title      string   `json:"title"`
-	NoteType   string   `json:"note_type"`
-	Tags       []string `json:"tags"`
-	TaskStatus string   `json:"task_status"`
-	Links      []string `json:"Links"`
-	CreatedAt  string   `json:"created_at"`
-	UpdatedAt  string   `json:"updated_at"`
-	NotePath   string   `json:"note_path"`
-	Archived   bool     `json:"archived"`
-	Deleted    bool     `json:"deleted"`
+	ID          string   `json:"id"`
+	NoteID      string   `json:"note_id"`
+	Title       string   `json:"title"`
+	NoteType    string   `json:"note_type"`
+	Tags        []string `json:"tags"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Sequence    string   `json:"sequence,omitempty"` // Folgezettel position (`1a2`)
+	TaskStatus  string   `json:"task_status"`
+	Due         string   `json:"due,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	CompletedAt string   `json:"completed_at,omitempty"`
+	Recur       string   `json:"recur,omitempty"`
+	Links       Links    `json:"Links"`
+	AutoLinks   []string `json:"auto_links,omitempty"` // Links added by `zk link --auto`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	NotePath    string   `json:"note_path"`
+	Archived    bool     `json:"archived"`
+	Deleted     bool     `json:"deleted"`
+}
+
+// Copy the metadata edited in a note's front matter to its index entry
+func (z *Zettel) CopyFrontMatter(frontMatter FrontMatter) {
+	z.Title = frontMatter.Title
+	z.NoteType = frontMatter.Type
+	z.Tags = frontMatter.Tags
+	z.Aliases = frontMatter.Aliases
+	z.Sequence = frontMatter.Sequence
+	z.Links = frontMatter.Links
+	z.TaskStatus = frontMatter.TaskStatus
+	z.Due = frontMatter.Due
+	z.Priority = frontMatter.Priority
+	z.CompletedAt = frontMatter.CompletedAt
+	z.Recur = frontMatter.Recur
+	z.UpdatedAt = frontMatter.UpdatedAt
 }